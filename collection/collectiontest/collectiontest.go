@@ -0,0 +1,488 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package collectiontest implements conformance test suites for custom implementations of the
+// interfaces defined by the collection package.
+package collectiontest
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+)
+
+// Set is the interface a set implementation needs to satisfy to be tested by [RunSetSuite].
+// S is the concrete type of the set, e.g. collection.Set[int] implements Set[int, collection.Set[int]].
+type Set[T comparable, S any] interface {
+	collection.SetLike[T]
+	// Union returns a new set that is the union of this set and b.
+	Union(b S) S
+	// Intersection returns a new set that contains only the items that are present in both sets.
+	Intersection(b S) S
+	// Difference returns a new set that contains only the items that are present in this set but not in b.
+	Difference(b S) S
+	// SymmetricDifference returns a new set that contains only the items that are present in one set but not both.
+	SymmetricDifference(b S) S
+}
+
+// RunCollectionSuite tests that the collection returned by newCollection behaves like the reference [collection.Set].
+// newCollection must return a new empty collection every time it is called.
+// item must return a different item for every i >= 0, the suite uses values of i below 1000.
+// The suite checks insert and remove semantics, iteration and a randomised comparison against the reference set.
+func RunCollectionSuite[T comparable](t *testing.T, newCollection func() collection.MutableCollection[T], item func(i int) T) {
+	t.Helper()
+	t.Run("Empty", func(t *testing.T) { testCollectionEmpty(t, newCollection, item) })
+	t.Run("InsertRemove", func(t *testing.T) { testCollectionInsertRemove(t, newCollection, item) })
+	t.Run("Iterate", func(t *testing.T) { testCollectionIterate(t, newCollection, item) })
+	t.Run("Model", func(t *testing.T) { testCollectionModel(t, newCollection, item) })
+}
+
+// RunSetSuite tests that the set returned by newSet behaves like the reference [collection.Set].
+// newSet must return a new empty set every time it is called.
+// item must return a different item for every i >= 0, the suite uses values of i below 1000.
+// In addition to [RunCollectionSuite], the suite checks Items and set algebra including edge cases like
+// empty sets and using the same set for both operands.
+func RunSetSuite[T comparable, S Set[T, S]](t *testing.T, newSet func() S, item func(i int) T) {
+	t.Helper()
+	RunCollectionSuite(t, func() collection.MutableCollection[T] { return newSet() }, item)
+	t.Run("Items", func(t *testing.T) { testSetItems(t, newSet, item) })
+	t.Run("Algebra", func(t *testing.T) { testSetAlgebra(t, newSet, item) })
+	t.Run("AlgebraModel", func(t *testing.T) { testSetAlgebraModel(t, newSet, item) })
+}
+
+// RunMapSuite tests that the map returned by newMap behaves like a Go map.
+// newMap must return a new empty map every time it is called.
+// key must return a different key for every i >= 0, the suite uses values of i below 1000.
+// Keys that share prefixes (e.g. "", "a" and "ab") are useful to exercise tree based maps.
+// value must return a different value for every i >= 0, the suite uses values of i below 10000.
+// The suite checks put, get and delete semantics, iteration and a randomised comparison against a Go map.
+func RunMapSuite[K comparable, V comparable](t *testing.T, newMap func() collection.MutableMapLike[K, V],
+	key func(i int) K, value func(i int) V) {
+
+	t.Helper()
+	f := mapFactory[K, V]{newMap: newMap, key: key, value: value}
+	t.Run("Empty", func(t *testing.T) { testMapEmpty(t, f) })
+	t.Run("PutGetDelete", func(t *testing.T) { testMapPutGetDelete(t, f) })
+	t.Run("Iterate", func(t *testing.T) { testMapIterate(t, f) })
+	t.Run("Model", func(t *testing.T) { testMapModel(t, f) })
+}
+
+//-----------------------------------------------------------------------------
+
+// items returns the items for the indexes.
+func items[T comparable](item func(i int) T, indexes ...int) []T {
+	result := make([]T, 0, len(indexes))
+	for _, i := range indexes {
+		result = append(result, item(i))
+	}
+	return result
+}
+
+// checkCollection fails the test if the collection does not contain exactly the expected items.
+func checkCollection[T comparable](t *testing.T, name string, c collection.Collection[T], expected collection.Set[T]) {
+	t.Helper()
+	if c.Len() != expected.Len() {
+		t.Errorf("%s: Len() = %d, expected %d", name, c.Len(), expected.Len())
+	}
+
+	all := slices.Collect(c.All())
+	if got := collection.NewSetFrom(all); len(all) != got.Len() || !setsEqual(got, expected) {
+		t.Errorf("%s: All() = %v, expected %v", name, all, expected.Items())
+	}
+
+	for item := range expected.All() {
+		if !c.Contains(item) {
+			t.Errorf("%s: Contains(%v) = false, expected true", name, item)
+		}
+	}
+}
+
+// checkSet fails the test if the set does not contain exactly the expected items.
+func checkSet[T comparable](t *testing.T, name string, s collection.SetLike[T], expected collection.Set[T]) {
+	t.Helper()
+	checkCollection(t, name, s, expected)
+
+	items := s.Items()
+	if got := collection.NewSetFrom(items); len(items) != got.Len() || !setsEqual(got, expected) {
+		t.Errorf("%s: Items() = %v, expected %v", name, items, expected.Items())
+	}
+}
+
+func testCollectionEmpty[T comparable](t *testing.T, newCollection func() collection.MutableCollection[T], item func(i int) T) {
+	c := newCollection()
+	checkCollection(t, "empty collection", c, collection.NewSet[T]())
+	if c.Contains(item(0)) {
+		t.Errorf("Contains(%v) = true on an empty collection", item(0))
+	}
+	if c.Remove(item(0)) {
+		t.Errorf("Remove(%v) = true on an empty collection", item(0))
+	}
+	for item := range c.All() {
+		t.Errorf("All() returned %v for an empty collection", item)
+	}
+}
+
+func testCollectionInsertRemove[T comparable](t *testing.T, newCollection func() collection.MutableCollection[T], item func(i int) T) {
+	c := newCollection()
+	for _, x := range items(item, 5, 3, 9, 1, 0) {
+		if !c.Insert(x) {
+			t.Errorf("Insert(%v) = false for a new item", x)
+		}
+	}
+	if c.Insert(item(5)) {
+		t.Errorf("Insert(%v) = true for an existing item", item(5))
+	}
+	checkCollection(t, "after insert", c, collection.NewSetFrom(items(item, 5, 3, 9, 1, 0)))
+
+	if !c.Remove(item(3)) {
+		t.Errorf("Remove(%v) = false for an existing item", item(3))
+	}
+	if c.Remove(item(3)) {
+		t.Errorf("Remove(%v) = true for an item that was already removed", item(3))
+	}
+	if c.Remove(item(42)) {
+		t.Errorf("Remove(%v) = true for an item that was never inserted", item(42))
+	}
+	if c.Contains(item(3)) {
+		t.Errorf("Contains(%v) = true after removing it", item(3))
+	}
+	checkCollection(t, "after remove", c, collection.NewSetFrom(items(item, 5, 9, 1, 0)))
+
+	// Removing all the items must leave an empty collection that can be reused
+	for _, x := range items(item, 5, 9, 1, 0) {
+		c.Remove(x)
+	}
+	checkCollection(t, "after removing all", c, collection.NewSet[T]())
+	if !c.Insert(item(3)) {
+		t.Errorf("Insert(%v) = false after it was removed", item(3))
+	}
+	checkCollection(t, "after reinserting", c, collection.NewSetFrom(items(item, 3)))
+}
+
+func testCollectionIterate[T comparable](t *testing.T, newCollection func() collection.MutableCollection[T], item func(i int) T) {
+	c := newCollection()
+	for _, x := range items(item, 1, 2, 3, 4, 5) {
+		c.Insert(x)
+	}
+
+	count := 0
+	for range c.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("All() did not stop when the loop was exited early")
+	}
+}
+
+func testCollectionModel[T comparable](t *testing.T, newCollection func() collection.MutableCollection[T], item func(i int) T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	c := newCollection()
+	reference := collection.NewSet[T]()
+
+	for i := 0; i < 5000; i++ {
+		x := item(rnd.IntN(300))
+		switch rnd.IntN(4) {
+		case 0:
+			if got, expected := c.Remove(x), reference.Remove(x); got != expected {
+				t.Fatalf("operation %d: Remove(%v) = %v, expected %v", i, x, got, expected)
+			}
+		case 1:
+			if got, expected := c.Contains(x), reference.Contains(x); got != expected {
+				t.Fatalf("operation %d: Contains(%v) = %v, expected %v", i, x, got, expected)
+			}
+		default:
+			if got, expected := c.Insert(x), reference.Insert(x); got != expected {
+				t.Fatalf("operation %d: Insert(%v) = %v, expected %v", i, x, got, expected)
+			}
+		}
+		if c.Len() != reference.Len() {
+			t.Fatalf("operation %d: Len() = %d, expected %d", i, c.Len(), reference.Len())
+		}
+	}
+	checkCollection(t, "model", c, reference)
+}
+
+//-----------------------------------------------------------------------------
+
+func newSetFrom[T comparable, S Set[T, S]](newSet func() S, items ...T) S {
+	s := newSet()
+	for _, item := range items {
+		s.Insert(item)
+	}
+	return s
+}
+
+func testSetItems[T comparable, S Set[T, S]](t *testing.T, newSet func() S, item func(i int) T) {
+	expected := items(item, 1, 2, 3, 4, 5)
+	s := newSetFrom(newSet, expected...)
+	checkSet(t, "set", s, collection.NewSetFrom(expected))
+
+	// Items must return a new slice that does not affect the set
+	got := s.Items()
+	for i := range got {
+		got[i] = item(100)
+	}
+	checkSet(t, "after modifying Items()", s, collection.NewSetFrom(expected))
+}
+
+func testSetAlgebra[T comparable, S Set[T, S]](t *testing.T, newSet func() S, item func(i int) T) {
+	cases := []struct {
+		name string
+		a    []int
+		b    []int
+	}{
+		{name: "both empty"},
+		{name: "empty a", b: []int{1, 2}},
+		{name: "empty b", a: []int{1, 2}},
+		{name: "disjoint", a: []int{1, 3, 5}, b: []int{2, 4, 6}},
+		{name: "overlapping", a: []int{1, 3, 5, 42}, b: []int{2, 3, 6, 42}},
+		{name: "subset", a: []int{1, 2}, b: []int{1, 2, 3}},
+		{name: "equal", a: []int{1, 2, 3}, b: []int{3, 2, 1}},
+	}
+
+	for _, tc := range cases {
+		itemsA, itemsB := items(item, tc.a...), items(item, tc.b...)
+		refA, refB := collection.NewSetFrom(itemsA), collection.NewSetFrom(itemsB)
+		for _, op := range setOps[T, S]() {
+			a, b := newSetFrom(newSet, itemsA...), newSetFrom(newSet, itemsB...)
+			name := fmt.Sprintf("%s %s", tc.name, op.name)
+			checkSet(t, name, op.op(a, b), op.reference(refA, refB))
+
+			// The operands must not be modified
+			checkSet(t, name+" operand a", a, refA)
+			checkSet(t, name+" operand b", b, refB)
+		}
+	}
+
+	// Using the same set for both operands
+	selfItems := items(item, 1, 2, 3)
+	self := newSetFrom(newSet, selfItems...)
+	checkSet(t, "self Union", self.Union(self), collection.NewSetFrom(selfItems))
+	checkSet(t, "self Intersection", self.Intersection(self), collection.NewSetFrom(selfItems))
+	checkSet(t, "self Difference", self.Difference(self), collection.NewSet[T]())
+	checkSet(t, "self SymmetricDifference", self.SymmetricDifference(self), collection.NewSet[T]())
+	checkSet(t, "self operand", self, collection.NewSetFrom(selfItems))
+
+	// The result must be independent of the operands
+	a, b := newSetFrom(newSet, item(1), item(2)), newSetFrom(newSet, item(3))
+	c := a.Union(b)
+	c.Insert(item(4))
+	a.Remove(item(1))
+	checkSet(t, "independent result", c, collection.NewSetFrom(items(item, 1, 2, 3, 4)))
+	checkSet(t, "independent operand a", a, collection.NewSetFrom(items(item, 2)))
+	checkSet(t, "independent operand b", b, collection.NewSetFrom(items(item, 3)))
+}
+
+func testSetAlgebraModel[T comparable, S Set[T, S]](t *testing.T, newSet func() S, item func(i int) T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	randomItems := func() []T {
+		result := make([]T, 0, 50)
+		for j := 0; j < 50; j++ {
+			result = append(result, item(rnd.IntN(150)))
+		}
+		return result
+	}
+
+	for i := 0; i < 10; i++ {
+		itemsA, itemsB := randomItems(), randomItems()
+		a, b := newSetFrom(newSet, itemsA...), newSetFrom(newSet, itemsB...)
+		refA, refB := collection.NewSetFrom(itemsA), collection.NewSetFrom(itemsB)
+		for _, op := range setOps[T, S]() {
+			checkSet(t, fmt.Sprintf("model %d %s", i, op.name), op.op(a, b), op.reference(refA, refB))
+		}
+	}
+}
+
+type setOp[T comparable, S Set[T, S]] struct {
+	name      string
+	op        func(a S, b S) S
+	reference func(a collection.Set[T], b collection.Set[T]) collection.Set[T]
+}
+
+func setOps[T comparable, S Set[T, S]]() []setOp[T, S] {
+	return []setOp[T, S]{
+		{"Union", S.Union, collection.Set[T].Union},
+		{"Intersection", S.Intersection, collection.Set[T].Intersection},
+		{"Difference", S.Difference, collection.Set[T].Difference},
+		{"SymmetricDifference", S.SymmetricDifference, collection.Set[T].SymmetricDifference},
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// mapFactory creates the maps, keys and values used by the map suite.
+type mapFactory[K comparable, V comparable] struct {
+	newMap func() collection.MutableMapLike[K, V]
+	key    func(i int) K
+	value  func(i int) V
+}
+
+// checkMap fails the test if the map does not contain exactly the expected key-value pairs.
+func checkMap[K comparable, V comparable](t *testing.T, name string, m collection.MutableMapLike[K, V], expected map[K]V) {
+	t.Helper()
+	if m.Len() != len(expected) {
+		t.Errorf("%s: Len() = %d, expected %d", name, m.Len(), len(expected))
+	}
+
+	all := make(map[K]V)
+	for k, v := range m.All() {
+		if _, exists := all[k]; exists {
+			t.Errorf("%s: All() returned the key %v more than once", name, k)
+		}
+		all[k] = v
+	}
+	if !maps.Equal(all, expected) {
+		t.Errorf("%s: All() = %v, expected %v", name, all, expected)
+	}
+
+	for k, expectedValue := range expected {
+		if v, ok := m.Get(k); !ok || v != expectedValue {
+			t.Errorf("%s: Get(%v) = %v, %v, expected %v, true", name, k, v, ok, expectedValue)
+		}
+		if !m.Contains(k) {
+			t.Errorf("%s: Contains(%v) = false, expected true", name, k)
+		}
+	}
+}
+
+func testMapEmpty[K comparable, V comparable](t *testing.T, f mapFactory[K, V]) {
+	m := f.newMap()
+	checkMap(t, "empty map", m, map[K]V{})
+	for _, k := range items(f.key, 0, 1) {
+		if m.Contains(k) {
+			t.Errorf("Contains(%v) = true on an empty map", k)
+		}
+		var zero V
+		if v, ok := m.Get(k); ok || v != zero {
+			t.Errorf("Get(%v) = %v, %v on an empty map", k, v, ok)
+		}
+		if m.Delete(k) {
+			t.Errorf("Delete(%v) = true on an empty map", k)
+		}
+	}
+}
+
+func testMapPutGetDelete[K comparable, V comparable](t *testing.T, f mapFactory[K, V]) {
+	m := f.newMap()
+	expected := make(map[K]V)
+	for i := 0; i < 6; i++ {
+		expected[f.key(i)] = f.value(i)
+	}
+	for k, v := range expected {
+		if !m.Put(k, v) {
+			t.Errorf("Put(%v) = false for a new key", k)
+		}
+	}
+	checkMap(t, "after put", m, expected)
+
+	if m.Put(f.key(2), f.value(20)) {
+		t.Errorf("Put(%v) = true for an existing key", f.key(2))
+	}
+	expected[f.key(2)] = f.value(20)
+	checkMap(t, "after replace", m, expected)
+
+	for _, k := range items(f.key, 1, 0) {
+		if !m.Delete(k) {
+			t.Errorf("Delete(%v) = false for an existing key", k)
+		}
+		if m.Delete(k) {
+			t.Errorf("Delete(%v) = true for a key that was already deleted", k)
+		}
+		if m.Contains(k) {
+			t.Errorf("Contains(%v) = true after deleting it", k)
+		}
+		delete(expected, k)
+	}
+	if m.Delete(f.key(6)) {
+		t.Errorf("Delete(%v) = true for a key that was never stored", f.key(6))
+	}
+	checkMap(t, "after delete", m, expected)
+
+	for k := range expected {
+		m.Delete(k)
+	}
+	checkMap(t, "after deleting all", m, map[K]V{})
+	if !m.Put(f.key(1), f.value(1)) {
+		t.Errorf("Put(%v) = false after it was deleted", f.key(1))
+	}
+	checkMap(t, "after reinserting", m, map[K]V{f.key(1): f.value(1)})
+}
+
+func testMapIterate[K comparable, V comparable](t *testing.T, f mapFactory[K, V]) {
+	m := f.newMap()
+	for i := 0; i < 10; i++ {
+		m.Put(f.key(i), f.value(i))
+	}
+
+	count := 0
+	for range m.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("All() did not stop when the loop was exited early")
+	}
+}
+
+func testMapModel[K comparable, V comparable](t *testing.T, f mapFactory[K, V]) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	m := f.newMap()
+	reference := make(map[K]V)
+
+	for i := 0; i < 5000; i++ {
+		k := f.key(rnd.IntN(300))
+		_, exists := reference[k]
+		switch rnd.IntN(4) {
+		case 0:
+			if got := m.Delete(k); got != exists {
+				t.Fatalf("operation %d: Delete(%v) = %v, expected %v", i, k, got, exists)
+			}
+			delete(reference, k)
+		case 1:
+			if v, ok := m.Get(k); ok != exists || v != reference[k] {
+				t.Fatalf("operation %d: Get(%v) = %v, %v, expected %v, %v", i, k, v, ok, reference[k], exists)
+			}
+		default:
+			v := f.value(i)
+			if got := m.Put(k, v); got != !exists {
+				t.Fatalf("operation %d: Put(%v) = %v, expected %v", i, k, got, !exists)
+			}
+			reference[k] = v
+		}
+		if m.Len() != len(reference) {
+			t.Fatalf("operation %d: Len() = %d, expected %d", i, m.Len(), len(reference))
+		}
+	}
+	checkMap(t, "model", m, reference)
+}
+
+//-----------------------------------------------------------------------------
+
+func setsEqual[T comparable](a collection.Set[T], b collection.Set[T]) bool {
+	return a.Len() == b.Len() && a.ContainsSlice(b.Items())
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collectiontest_test
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/andrejacobs/go-collection/collection/collectiontest"
+)
+
+// The conformance of the implementations in the collection package is tested in interfaces_test.go.
+// These tests check that the suites can be used by custom implementations like the ones below.
+
+// mapSet is a minimal set that is not based on collection.Set.
+type mapSet[T comparable] map[T]bool
+
+func (s mapSet[T]) Len() int {
+	return len(s)
+}
+
+func (s mapSet[T]) Contains(item T) bool {
+	return s[item]
+}
+
+func (s mapSet[T]) All() iter.Seq[T] {
+	return maps.Keys(s)
+}
+
+func (s mapSet[T]) Items() []T {
+	return slices.Collect(maps.Keys(s))
+}
+
+func (s mapSet[T]) Insert(item T) bool {
+	if s[item] {
+		return false
+	}
+	s[item] = true
+	return true
+}
+
+func (s mapSet[T]) Remove(item T) bool {
+	existed := s[item]
+	delete(s, item)
+	return existed
+}
+
+func (s mapSet[T]) Union(b mapSet[T]) mapSet[T] {
+	result := maps.Clone(s)
+	maps.Copy(result, b)
+	return result
+}
+
+func (s mapSet[T]) Intersection(b mapSet[T]) mapSet[T] {
+	result := make(mapSet[T])
+	for item := range s {
+		if b[item] {
+			result[item] = true
+		}
+	}
+	return result
+}
+
+func (s mapSet[T]) Difference(b mapSet[T]) mapSet[T] {
+	result := make(mapSet[T])
+	for item := range s {
+		if !b[item] {
+			result[item] = true
+		}
+	}
+	return result
+}
+
+func (s mapSet[T]) SymmetricDifference(b mapSet[T]) mapSet[T] {
+	return s.Difference(b).Union(b.Difference(s))
+}
+
+// goMap is a minimal map that is not based on any of the maps in the collection package.
+type goMap[K comparable, V any] map[K]V
+
+func (m goMap[K, V]) Len() int {
+	return len(m)
+}
+
+func (m goMap[K, V]) Contains(key K) bool {
+	_, exists := m[key]
+	return exists
+}
+
+func (m goMap[K, V]) Get(key K) (V, bool) {
+	v, exists := m[key]
+	return v, exists
+}
+
+func (m goMap[K, V]) All() iter.Seq2[K, V] {
+	return maps.All(m)
+}
+
+func (m goMap[K, V]) Put(key K, value V) bool {
+	_, exists := m[key]
+	m[key] = value
+	return !exists
+}
+
+func (m goMap[K, V]) Delete(key K) bool {
+	_, exists := m[key]
+	delete(m, key)
+	return exists
+}
+
+type point struct{ x, y int }
+
+func TestSetSuite(t *testing.T) {
+	collectiontest.RunSetSuite(t, func() mapSet[string] {
+		return make(mapSet[string])
+	}, func(i int) string {
+		return fmt.Sprintf("item/%d", i)
+	})
+}
+
+func TestCollectionSuite(t *testing.T) {
+	collectiontest.RunCollectionSuite(t, func() collection.MutableCollection[point] {
+		return make(mapSet[point])
+	}, func(i int) point {
+		return point{x: i % 7, y: i / 7}
+	})
+}
+
+func TestMapSuite(t *testing.T) {
+	collectiontest.RunMapSuite(t, func() collection.MutableMapLike[point, string] {
+		return make(goMap[point, string])
+	}, func(i int) point {
+		return point{x: i % 7, y: i / 7}
+	}, func(i int) string {
+		return fmt.Sprintf("value/%d", i)
+	})
+}
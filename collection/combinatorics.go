@@ -0,0 +1,257 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// See https://en.wikipedia.org/wiki/Combination and https://en.wikipedia.org/wiki/Permutation
+// for the definitions used.
+
+// Product returns an iterator over the cartesian product of the slices, i.e. every possible tuple
+// that contains one element from each slice, in lexicographic order of the slice positions.
+// For example Product([]int{1, 2}, []int{3, 4}) produces [1 3], [1 4], [2 3], [2 4].
+// Nothing is produced if any of the slices are empty and a single empty tuple is produced if no
+// slices are given. Each tuple is a new slice.
+func Product[T any](inputs ...[]T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for _, s := range inputs {
+			if len(s) == 0 {
+				return
+			}
+		}
+
+		indices := make([]int, len(inputs))
+		for {
+			tuple := make([]T, len(inputs))
+			for i, j := range indices {
+				tuple[i] = inputs[i][j]
+			}
+			if !yield(tuple) {
+				return
+			}
+
+			// Advance the indices like an odometer with the last position changing the fastest
+			i := len(indices) - 1
+			for ; i >= 0; i-- {
+				indices[i]++
+				if indices[i] < len(inputs[i]) {
+					break
+				}
+				indices[i] = 0
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
+
+// Combinations returns an iterator over all the ways of choosing k elements from s where the order
+// does not matter. The elements in each combination keep their order from s and the combinations are
+// produced in lexicographic order of the element positions.
+// For example Combinations([]int{1, 2, 3}, 2) produces [1 2], [1 3], [2 3].
+// Elements are treated as unique based on their position and not their value.
+// Nothing is produced if k < 0 or k > len(s). Each combination is a new slice.
+func Combinations[T any](s []T, k int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(s)
+		if k < 0 || k > n {
+			return
+		}
+
+		indices := make([]int, k)
+		for i := range indices {
+			indices[i] = i
+		}
+		for {
+			if !yield(pick(s, indices)) {
+				return
+			}
+
+			// Find the rightmost index that has not reached its maximum value
+			i := k - 1
+			for i >= 0 && indices[i] == i+n-k {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[j-1] + 1
+			}
+		}
+	}
+}
+
+// CombinationsWithReplacement returns an iterator over all the ways of choosing k elements from s
+// where the order does not matter and each element may be chosen more than once.
+// For example CombinationsWithReplacement([]int{1, 2}, 2) produces [1 1], [1 2], [2 2].
+// Nothing is produced if k < 0 or if s is empty and k > 0. Each combination is a new slice.
+func CombinationsWithReplacement[T any](s []T, k int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(s)
+		if k < 0 || (n == 0 && k > 0) {
+			return
+		}
+
+		indices := make([]int, k)
+		for {
+			if !yield(pick(s, indices)) {
+				return
+			}
+
+			i := k - 1
+			for i >= 0 && indices[i] == n-1 {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[i]
+			}
+		}
+	}
+}
+
+// Permutations returns an iterator over all the ordered arrangements of k elements chosen from s
+// in lexicographic order of the element positions.
+// For example Permutations([]int{1, 2, 3}, 2) produces [1 2], [1 3], [2 1], [2 3], [3 1], [3 2].
+// Use k = len(s) for all the permutations of the whole slice.
+// Elements are treated as unique based on their position and not their value.
+// Nothing is produced if k < 0 or k > len(s). Each permutation is a new slice.
+func Permutations[T any](s []T, k int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(s)
+		if k < 0 || k > n {
+			return
+		}
+
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		cycles := make([]int, k)
+		for i := range cycles {
+			cycles[i] = n - i
+		}
+
+		if !yield(pick(s, indices[:k])) {
+			return
+		}
+		for {
+			i := k - 1
+			for ; i >= 0; i-- {
+				cycles[i]--
+				if cycles[i] == 0 {
+					// Move indices[i] to the end and reset this position
+					first := indices[i]
+					copy(indices[i:], indices[i+1:])
+					indices[n-1] = first
+					cycles[i] = n - i
+					continue
+				}
+
+				j := n - cycles[i]
+				indices[i], indices[j] = indices[j], indices[i]
+				if !yield(pick(s, indices[:k])) {
+					return
+				}
+				break
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
+
+// PowerSet returns an iterator over all the subsets of the set, including the empty set and the set itself.
+// A set with n items has 2^n subsets. The order in which the subsets are produced is not specified.
+// Each subset is a new set.
+func PowerSet[T comparable](s Set[T]) iter.Seq[Set[T]] {
+	return func(yield func(Set[T]) bool) {
+		items := s.Items()
+		included := make([]bool, len(items))
+		for {
+			subset := NewSet[T]()
+			for i, item := range items {
+				if included[i] {
+					subset.Insert(item)
+				}
+			}
+			if !yield(subset) {
+				return
+			}
+
+			// Increment the binary counter represented by included
+			i := 0
+			for ; i < len(included) && included[i]; i++ {
+				included[i] = false
+			}
+			if i == len(included) {
+				return
+			}
+			included[i] = true
+		}
+	}
+}
+
+// NextPermutation rearranges the elements of s into the next permutation in lexicographic order.
+// Returns false if s was already the last permutation (sorted in descending order), in which case
+// s is rearranged into the first permutation (sorted in ascending order).
+// Repeatedly calling NextPermutation on a sorted slice visits every distinct permutation once,
+// even when the slice contains duplicate values.
+// NOTE: The slice is modified in place.
+func NextPermutation[T cmp.Ordered](s []T) bool {
+	// Find the rightmost element that is smaller than its successor
+	i := len(s) - 2
+	for i >= 0 && s[i] >= s[i+1] {
+		i--
+	}
+	if i < 0 {
+		slices.Reverse(s)
+		return false
+	}
+
+	// Swap it with the rightmost element that is bigger, then reverse the descending suffix
+	j := len(s) - 1
+	for s[j] <= s[i] {
+		j--
+	}
+	s[i], s[j] = s[j], s[i]
+	slices.Reverse(s[i+1:])
+	return true
+}
+
+// pick returns a new slice with the elements of s at the indices.
+func pick[T any](s []T, indices []int) []T {
+	result := make([]T, len(indices))
+	for i, j := range indices {
+		result[i] = s[j]
+	}
+	return result
+}
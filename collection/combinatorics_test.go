@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProduct(t *testing.T) {
+	result := slices.Collect(collection.Product([]int{1, 2}, []int{3, 4}, []int{5}))
+	assert.Equal(t, [][]int{{1, 3, 5}, {1, 4, 5}, {2, 3, 5}, {2, 4, 5}}, result)
+
+	assert.Equal(t, [][]int{{}}, slices.Collect(collection.Product[int]()))
+	assert.Empty(t, slices.Collect(collection.Product([]int{1, 2}, []int{})))
+	assert.Len(t, slices.Collect(collection.Product([]int{1, 2, 3}, []int{1, 2, 3}, []int{1, 2, 3})), 27)
+
+	count := 0
+	for range collection.Product([]int{1, 2}, []int{3, 4}) {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestCombinations(t *testing.T) {
+	s := []string{"a", "b", "c", "d"}
+	assert.Equal(t, [][]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"}},
+		slices.Collect(collection.Combinations(s, 2)))
+	assert.Equal(t, [][]string{{"a", "b", "c", "d"}}, slices.Collect(collection.Combinations(s, 4)))
+	assert.Equal(t, [][]string{{}}, slices.Collect(collection.Combinations(s, 0)))
+	assert.Empty(t, slices.Collect(collection.Combinations(s, 5)))
+	assert.Empty(t, slices.Collect(collection.Combinations(s, -1)))
+
+	// n choose k
+	assert.Len(t, slices.Collect(collection.Combinations(make([]int, 10), 3)), 120)
+}
+
+func TestCombinationsWithReplacement(t *testing.T) {
+	s := []int{1, 2, 3}
+	assert.Equal(t, [][]int{{1, 1}, {1, 2}, {1, 3}, {2, 2}, {2, 3}, {3, 3}},
+		slices.Collect(collection.CombinationsWithReplacement(s, 2)))
+	assert.Equal(t, [][]int{{}}, slices.Collect(collection.CombinationsWithReplacement(s, 0)))
+	assert.Len(t, slices.Collect(collection.CombinationsWithReplacement(s, 5)), 21)
+	assert.Empty(t, slices.Collect(collection.CombinationsWithReplacement([]int{}, 1)))
+	assert.Empty(t, slices.Collect(collection.CombinationsWithReplacement(s, -1)))
+}
+
+func TestPermutations(t *testing.T) {
+	s := []int{1, 2, 3}
+	assert.Equal(t, [][]int{{1, 2}, {1, 3}, {2, 1}, {2, 3}, {3, 1}, {3, 2}}, slices.Collect(collection.Permutations(s, 2)))
+	assert.Equal(t, [][]int{{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1}},
+		slices.Collect(collection.Permutations(s, 3)))
+	assert.Equal(t, [][]int{{}}, slices.Collect(collection.Permutations(s, 0)))
+	assert.Empty(t, slices.Collect(collection.Permutations(s, 4)))
+	assert.Len(t, slices.Collect(collection.Permutations(make([]int, 6), 6)), 720)
+	assert.Len(t, slices.Collect(collection.Permutations(make([]int, 6), 3)), 120)
+
+	// The input is never modified
+	for p := range collection.Permutations(s, 3) {
+		p[0] = 100
+	}
+	assert.Equal(t, []int{1, 2, 3}, s)
+}
+
+func TestPowerSet(t *testing.T) {
+	var subsets [][]int
+	for subset := range collection.PowerSet(collection.NewSetFrom([]int{1, 2, 3})) {
+		items := subset.Items()
+		sort.Ints(items)
+		subsets = append(subsets, items)
+	}
+	assert.ElementsMatch(t, [][]int{{}, {1}, {2}, {3}, {1, 2}, {1, 3}, {2, 3}, {1, 2, 3}}, subsets)
+
+	count := 0
+	for subset := range collection.PowerSet(collection.NewSet[int]()) {
+		assert.Equal(t, 0, subset.Len())
+		count++
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestNextPermutation(t *testing.T) {
+	s := []int{1, 2, 3}
+	var all [][]int
+	for {
+		all = append(all, slices.Clone(s))
+		if !collection.NextPermutation(s) {
+			break
+		}
+	}
+	assert.Equal(t, slices.Collect(collection.Permutations([]int{1, 2, 3}, 3)), all)
+	assert.Equal(t, []int{1, 2, 3}, s)
+
+	// Duplicates are only visited once
+	d := []string{"a", "a", "b"}
+	count := 1
+	for collection.NextPermutation(d) {
+		count++
+	}
+	assert.Equal(t, 3, count)
+
+	assert.False(t, collection.NextPermutation([]int{}))
+	assert.False(t, collection.NextPermutation([]int{1}))
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"iter"
+	"slices"
+)
+
+// See https://en.wikipedia.org/wiki/Disjoint-set_data_structure for the data structure.
+
+// DisjointSet (union-find) partitions items into disjoint groups and can quickly merge groups
+// and check whether two items are in the same group.
+// It uses path compression and union by rank which makes all operations run in nearly O(1)
+// amortised time.
+// The zero value is not usable, create a new disjoint set using [NewDisjointSet].
+type DisjointSet[T comparable] struct {
+	index  map[T]int
+	items  []T
+	parent []int
+	rank   []uint8
+	size   []int
+	sets   int
+}
+
+// NewDisjointSet creates a new disjoint set where each of the items are in their own group.
+func NewDisjointSet[T comparable](items ...T) *DisjointSet[T] {
+	d := &DisjointSet[T]{
+		index: make(map[T]int, len(items)),
+	}
+	for _, item := range items {
+		d.Add(item)
+	}
+	return d
+}
+
+// Len returns the number of items.
+func (d *DisjointSet[T]) Len() int {
+	return len(d.items)
+}
+
+// SetCount returns the number of disjoint groups.
+func (d *DisjointSet[T]) SetCount() int {
+	return d.sets
+}
+
+// Add the item in a new group of its own.
+// Returns true if the item was added and false if the item is already known.
+func (d *DisjointSet[T]) Add(item T) bool {
+	if _, exists := d.index[item]; exists {
+		return false
+	}
+	d.add(item)
+	return true
+}
+
+// Contains returns true if the item is known.
+func (d *DisjointSet[T]) Contains(item T) bool {
+	_, exists := d.index[item]
+	return exists
+}
+
+// All returns an iterator over all the items in the order they were added.
+func (d *DisjointSet[T]) All() iter.Seq[T] {
+	return slices.Values(d.items)
+}
+
+// Find returns the representative item of the group that the item belongs to.
+// Two items are in the same group if they have the same representative.
+// Returns false if the item is not known.
+func (d *DisjointSet[T]) Find(item T) (T, bool) {
+	i, exists := d.index[item]
+	if !exists {
+		var zero T
+		return zero, false
+	}
+	return d.items[d.find(i)], true
+}
+
+// Union merges the groups that a and b belong to. Items that are not known are added first.
+// Returns true if the groups were merged and false if a and b were already in the same group.
+func (d *DisjointSet[T]) Union(a T, b T) bool {
+	ra := d.find(d.indexOf(a))
+	rb := d.find(d.indexOf(b))
+	if ra == rb {
+		return false
+	}
+
+	if d.rank[ra] < d.rank[rb] {
+		ra, rb = rb, ra
+	}
+	d.parent[rb] = ra
+	d.size[ra] += d.size[rb]
+	if d.rank[ra] == d.rank[rb] {
+		d.rank[ra]++
+	}
+	d.sets--
+	return true
+}
+
+// Connected returns true if a and b are known and in the same group.
+func (d *DisjointSet[T]) Connected(a T, b T) bool {
+	ia, okA := d.index[a]
+	ib, okB := d.index[b]
+	return okA && okB && d.find(ia) == d.find(ib)
+}
+
+// SetSize returns the number of items in the group that the item belongs to.
+// Returns 0 if the item is not known.
+func (d *DisjointSet[T]) SetSize(item T) int {
+	i, exists := d.index[item]
+	if !exists {
+		return 0
+	}
+	return d.size[d.find(i)]
+}
+
+// Groups returns each of the disjoint groups as a [Set].
+func (d *DisjointSet[T]) Groups() []Set[T] {
+	groups := make(map[int]Set[T], d.sets)
+	result := make([]Set[T], 0, d.sets)
+	for i, item := range d.items {
+		root := d.find(i)
+		group, exists := groups[root]
+		if !exists {
+			group = NewSetWithCapacity[T](d.size[root])
+			groups[root] = group
+			result = append(result, group)
+		}
+		group.Insert(item)
+	}
+	return result
+}
+
+//-----------------------------------------------------------------------------
+
+func (d *DisjointSet[T]) add(item T) int {
+	i := len(d.items)
+	d.index[item] = i
+	d.items = append(d.items, item)
+	d.parent = append(d.parent, i)
+	d.rank = append(d.rank, 0)
+	d.size = append(d.size, 1)
+	d.sets++
+	return i
+}
+
+// indexOf returns the index of the item and adds it if needed.
+func (d *DisjointSet[T]) indexOf(item T) int {
+	if i, exists := d.index[item]; exists {
+		return i
+	}
+	return d.add(item)
+}
+
+// find returns the root of i while compressing the path.
+func (d *DisjointSet[T]) find(i int) int {
+	root := i
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+	for d.parent[i] != root {
+		d.parent[i], i = root, d.parent[i]
+	}
+	return root
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisjointSet(t *testing.T) {
+	d := collection.NewDisjointSet("a", "b", "c", "d", "e")
+	assert.Equal(t, 5, d.Len())
+	assert.Equal(t, 5, d.SetCount())
+	assert.False(t, d.Add("a"))
+	assert.True(t, d.Contains("e"))
+	assert.False(t, d.Contains("z"))
+
+	assert.True(t, d.Union("a", "b"))
+	assert.True(t, d.Union("c", "d"))
+	assert.False(t, d.Union("b", "a"))
+	assert.Equal(t, 3, d.SetCount())
+
+	assert.True(t, d.Connected("a", "b"))
+	assert.False(t, d.Connected("a", "c"))
+	assert.False(t, d.Connected("a", "z"))
+
+	assert.True(t, d.Union("b", "d"))
+	assert.True(t, d.Connected("a", "c"))
+	assert.Equal(t, 4, d.SetSize("c"))
+	assert.Equal(t, 1, d.SetSize("e"))
+	assert.Equal(t, 0, d.SetSize("z"))
+
+	ra, ok := d.Find("a")
+	assert.True(t, ok)
+	rd, _ := d.Find("d")
+	assert.Equal(t, ra, rd)
+	_, ok = d.Find("z")
+	assert.False(t, ok)
+
+	// Unknown items are added by Union
+	assert.True(t, d.Union("e", "f"))
+	assert.Equal(t, 6, d.Len())
+	assert.Equal(t, 2, d.SetCount())
+}
+
+func TestDisjointSetGroups(t *testing.T) {
+	d := collection.NewDisjointSet[int]()
+	for i := 0; i < 10; i++ {
+		d.Union(i, i%3)
+	}
+
+	groups := d.Groups()
+	assert.Len(t, groups, 3)
+
+	var sorted [][]int
+	for _, g := range groups {
+		items := g.Items()
+		slices.Sort(items)
+		sorted = append(sorted, items)
+	}
+	slices.SortFunc(sorted, func(a, b []int) int { return a[0] - b[0] })
+	assert.Equal(t, [][]int{{0, 3, 6, 9}, {1, 4, 7}, {2, 5, 8}}, sorted)
+}
+
+func TestDisjointSetBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	const n = 100
+	d := collection.NewDisjointSet[int]()
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = i
+		d.Add(i)
+	}
+
+	for op := 0; op < 200; op++ {
+		a, b := rnd.IntN(n), rnd.IntN(n)
+		merged := labels[a] != labels[b]
+		assert.Equal(t, merged, d.Union(a, b))
+		if merged {
+			old := labels[b]
+			for i := range labels {
+				if labels[i] == old {
+					labels[i] = labels[a]
+				}
+			}
+		}
+
+		x, y := rnd.IntN(n), rnd.IntN(n)
+		assert.Equal(t, labels[x] == labels[y], d.Connected(x, y))
+	}
+
+	assert.Equal(t, collection.NewSetFrom(labels).Len(), d.SetCount())
+}
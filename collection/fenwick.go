@@ -0,0 +1,89 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+// See https://en.wikipedia.org/wiki/Fenwick_tree for the data structure.
+
+// FenwickTree (binary indexed tree) maintains prefix sums over a fixed size array of numbers
+// where both changing an element and calculating a prefix sum take O(log n) time.
+// It uses less memory than a [SegmentTree] but only supports sums.
+// Ranges are half-open [lo, hi) like Go slices. Indices that are out of bounds will panic.
+type FenwickTree[T Number] struct {
+	tree []T // 1-based
+}
+
+// NewFenwickTree creates a new Fenwick tree with n elements that are all 0.
+func NewFenwickTree[T Number](n int) *FenwickTree[T] {
+	return &FenwickTree[T]{
+		tree: make([]T, n+1),
+	}
+}
+
+// NewFenwickTreeFrom creates a new Fenwick tree from the values in O(n) time.
+func NewFenwickTreeFrom[T Number](values []T) *FenwickTree[T] {
+	t := NewFenwickTree[T](len(values))
+	copy(t.tree[1:], values)
+	for i := 1; i < len(t.tree); i++ {
+		parent := i + (i & -i)
+		if parent < len(t.tree) {
+			t.tree[parent] += t.tree[i]
+		}
+	}
+	return t
+}
+
+// Len returns the number of elements.
+func (t *FenwickTree[T]) Len() int {
+	return len(t.tree) - 1
+}
+
+// Add delta to the element at index i.
+func (t *FenwickTree[T]) Add(i int, delta T) {
+	checkIndex(i, t.Len())
+	for i++; i < len(t.tree); i += i & -i {
+		t.tree[i] += delta
+	}
+}
+
+// Get returns the element at index i.
+func (t *FenwickTree[T]) Get(i int) T {
+	return t.RangeSum(i, i+1)
+}
+
+// Set changes the element at index i.
+func (t *FenwickTree[T]) Set(i int, value T) {
+	t.Add(i, value-t.Get(i))
+}
+
+// PrefixSum returns the sum of the first n elements, i.e. the range [0, n).
+func (t *FenwickTree[T]) PrefixSum(n int) T {
+	checkRange(0, n, t.Len())
+	var sum T
+	for ; n > 0; n -= n & -n {
+		sum += t.tree[n]
+	}
+	return sum
+}
+
+// RangeSum returns the sum of the elements in the range [lo, hi).
+func (t *FenwickTree[T]) RangeSum(lo int, hi int) T {
+	checkRange(lo, hi, t.Len())
+	return t.PrefixSum(hi) - t.PrefixSum(lo)
+}
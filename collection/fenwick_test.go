@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFenwickTree(t *testing.T) {
+	tr := collection.NewFenwickTreeFrom([]int{3, 2, -1, 6, 5, 4, -3, 3, 7, 2, 3})
+	assert.Equal(t, 11, tr.Len())
+	assert.Equal(t, 0, tr.PrefixSum(0))
+	assert.Equal(t, 3, tr.PrefixSum(1))
+	assert.Equal(t, 31, tr.PrefixSum(11))
+	assert.Equal(t, 15, tr.RangeSum(3, 6))
+	assert.Equal(t, 6, tr.Get(3))
+
+	tr.Add(3, 4)
+	assert.Equal(t, 10, tr.Get(3))
+	assert.Equal(t, 35, tr.PrefixSum(11))
+
+	tr.Set(0, 0)
+	assert.Equal(t, 32, tr.PrefixSum(11))
+
+	assert.Panics(t, func() { tr.Add(11, 1) })
+	assert.Panics(t, func() { tr.PrefixSum(12) })
+	assert.Panics(t, func() { tr.RangeSum(5, 4) })
+}
+
+func TestFenwickTreeFloat(t *testing.T) {
+	tr := collection.NewFenwickTree[float64](4)
+	tr.Add(0, 0.5)
+	tr.Add(3, 1.25)
+	assert.InDelta(t, 1.75, tr.PrefixSum(4), 1e-9)
+	assert.InDelta(t, 1.25, tr.RangeSum(1, 4), 1e-9)
+}
+
+func TestFenwickTreeBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(7, 8))
+	const n = 50
+	values := make([]int64, n)
+	tr := collection.NewFenwickTree[int64](n)
+
+	for op := 0; op < 2000; op++ {
+		i := rnd.IntN(n)
+		delta := int64(rnd.IntN(200) - 100)
+		tr.Add(i, delta)
+		values[i] += delta
+
+		lo := rnd.IntN(n + 1)
+		hi := lo + rnd.IntN(n-lo+1)
+		var expected int64
+		for _, v := range values[lo:hi] {
+			expected += v
+		}
+		require.Equal(t, expected, tr.RangeSum(lo, hi))
+	}
+
+	built := collection.NewFenwickTreeFrom(values)
+	assert.Equal(t, tr.PrefixSum(n), built.PrefixSum(n))
+}
@@ -0,0 +1,413 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// See https://en.wikipedia.org/wiki/Directed_graph for graph theory.
+
+// Graph is a directed graph where the nodes are of type N.
+// The successors and predecessors of each node are stored as a [Set].
+// Methods that return nodes do so in the order that the nodes were added to the graph, which
+// makes the results (e.g. a topological sort) deterministic.
+// The zero value is not usable, create a new graph using [NewGraph].
+type Graph[N comparable] struct {
+	nodes map[N]int // insertion sequence used to order nodes
+	seq   int
+	out   map[N]Set[N]
+	in    map[N]Set[N]
+	edges int
+}
+
+// CycleError is returned by graph algorithms that require the graph to be acyclic.
+type CycleError[N comparable] struct {
+	// Cycle lists the nodes that make up the cycle where the first node is repeated at the end,
+	// e.g. [a b c a].
+	Cycle []N
+}
+
+func (e *CycleError[N]) Error() string {
+	parts := make([]string, 0, len(e.Cycle))
+	for _, n := range e.Cycle {
+		parts = append(parts, fmt.Sprint(n))
+	}
+	return fmt.Sprintf("graph contains a cycle: %s", strings.Join(parts, " -> "))
+}
+
+// NewGraph creates a new empty directed graph.
+func NewGraph[N comparable]() *Graph[N] {
+	return &Graph[N]{
+		nodes: make(map[N]int),
+		out:   make(map[N]Set[N]),
+		in:    make(map[N]Set[N]),
+	}
+}
+
+// Len returns the number of nodes.
+func (g *Graph[N]) Len() int {
+	return len(g.nodes)
+}
+
+// EdgeCount returns the number of edges.
+func (g *Graph[N]) EdgeCount() int {
+	return g.edges
+}
+
+// AddNode adds the node to the graph.
+// Returns true if the node was added and false if it already exists.
+func (g *Graph[N]) AddNode(node N) bool {
+	if g.HasNode(node) {
+		return false
+	}
+	g.nodes[node] = g.seq
+	g.seq++
+	g.out[node] = NewSet[N]()
+	g.in[node] = NewSet[N]()
+	return true
+}
+
+// HasNode returns true if the node is in the graph.
+func (g *Graph[N]) HasNode(node N) bool {
+	_, exists := g.nodes[node]
+	return exists
+}
+
+// Contains returns true if the node is in the graph.
+// Same as [Graph.HasNode] and implements the [Collection] interface.
+func (g *Graph[N]) Contains(node N) bool {
+	return g.HasNode(node)
+}
+
+// All returns an iterator over all the nodes in the order they were added.
+func (g *Graph[N]) All() iter.Seq[N] {
+	return slices.Values(g.Nodes())
+}
+
+// RemoveNode removes the node and all the edges to and from it.
+// Returns true if the node was in the graph before removing.
+func (g *Graph[N]) RemoveNode(node N) bool {
+	if !g.HasNode(node) {
+		return false
+	}
+
+	for _, to := range g.out[node].Items() {
+		g.RemoveEdge(node, to)
+	}
+	for _, from := range g.in[node].Items() {
+		g.RemoveEdge(from, node)
+	}
+	delete(g.nodes, node)
+	delete(g.out, node)
+	delete(g.in, node)
+	return true
+}
+
+// AddEdge adds a directed edge from -> to. The nodes are added if they are not already in the graph.
+// Returns true if the edge was added and false if it already exists.
+func (g *Graph[N]) AddEdge(from N, to N) bool {
+	g.AddNode(from)
+	g.AddNode(to)
+	if !g.out[from].Insert(to) {
+		return false
+	}
+	g.in[to].Insert(from)
+	g.edges++
+	return true
+}
+
+// HasEdge returns true if the directed edge from -> to is in the graph.
+func (g *Graph[N]) HasEdge(from N, to N) bool {
+	successors, exists := g.out[from]
+	return exists && successors.Contains(to)
+}
+
+// RemoveEdge removes the directed edge from -> to.
+// Returns true if the edge was in the graph before removing.
+func (g *Graph[N]) RemoveEdge(from N, to N) bool {
+	if !g.HasEdge(from, to) {
+		return false
+	}
+	g.out[from].Remove(to)
+	g.in[to].Remove(from)
+	g.edges--
+	return true
+}
+
+// Nodes returns all the nodes in the order they were added.
+func (g *Graph[N]) Nodes() []N {
+	result := make([]N, 0, len(g.nodes))
+	for n := range g.nodes {
+		result = append(result, n)
+	}
+	g.sortNodes(result)
+	return result
+}
+
+// Edges returns all the edges as (from, to) pairs.
+func (g *Graph[N]) Edges() []Pair[N, N] {
+	result := make([]Pair[N, N], 0, g.edges)
+	for _, from := range g.Nodes() {
+		for _, to := range g.Successors(from) {
+			result = append(result, Pair[N, N]{First: from, Second: to})
+		}
+	}
+	return result
+}
+
+// Successors returns the nodes that have an edge from the node.
+func (g *Graph[N]) Successors(node N) []N {
+	return g.sortedItems(g.out[node])
+}
+
+// Predecessors returns the nodes that have an edge to the node.
+func (g *Graph[N]) Predecessors(node N) []N {
+	return g.sortedItems(g.in[node])
+}
+
+// OutDegree returns the number of edges from the node.
+func (g *Graph[N]) OutDegree(node N) int {
+	return g.out[node].Len()
+}
+
+// InDegree returns the number of edges to the node.
+func (g *Graph[N]) InDegree(node N) int {
+	return g.in[node].Len()
+}
+
+// Clone returns a copy of the graph.
+func (g *Graph[N]) Clone() *Graph[N] {
+	c := NewGraph[N]()
+	for _, n := range g.Nodes() {
+		c.AddNode(n)
+	}
+	for _, e := range g.Edges() {
+		c.AddEdge(e.First, e.Second)
+	}
+	return c
+}
+
+// TopologicalSort returns the nodes ordered such that for every edge from -> to, from comes before to.
+// A [*CycleError] is returned if the graph contains a cycle.
+func (g *Graph[N]) TopologicalSort() ([]N, error) {
+	inDegree := make(map[N]int, len(g.nodes))
+	queue := make([]N, 0, len(g.nodes))
+	for _, n := range g.Nodes() {
+		inDegree[n] = g.in[n].Len()
+		if inDegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+
+	result := make([]N, 0, len(g.nodes))
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		result = append(result, n)
+		for _, to := range g.Successors(n) {
+			inDegree[to]--
+			if inDegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	if len(result) < len(g.nodes) {
+		return nil, &CycleError[N]{Cycle: g.findCycle(inDegree)}
+	}
+	return result, nil
+}
+
+// IsAcyclic returns true if the graph does not contain any cycles.
+func (g *Graph[N]) IsAcyclic() bool {
+	_, err := g.TopologicalSort()
+	return err == nil
+}
+
+// Reachable returns all the nodes that can be reached by following one or more edges from the node.
+// The node itself is only included if it is part of a cycle.
+func (g *Graph[N]) Reachable(from N) Set[N] {
+	visited := NewSet[N]()
+	stack := g.Successors(from)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited.Insert(n) {
+			stack = append(stack, g.out[n].Items()...)
+		}
+	}
+	return visited
+}
+
+// StronglyConnectedComponents returns the groups of nodes where every node in a group can reach
+// every other node in the same group. Nodes that are not part of a cycle are in a group of their own.
+// The groups are returned in reverse topological order using Tarjan's algorithm.
+func (g *Graph[N]) StronglyConnectedComponents() [][]N {
+	index := make(map[N]int, len(g.nodes))
+	lowLink := make(map[N]int, len(g.nodes))
+	onStack := NewSet[N]()
+	stack := make([]N, 0)
+	result := make([][]N, 0)
+
+	var connect func(n N)
+	connect = func(n N) {
+		index[n] = len(index)
+		lowLink[n] = index[n]
+		stack = append(stack, n)
+		onStack.Insert(n)
+
+		for _, to := range g.Successors(n) {
+			if _, visited := index[to]; !visited {
+				connect(to)
+				lowLink[n] = min(lowLink[n], lowLink[to])
+			} else if onStack.Contains(to) {
+				lowLink[n] = min(lowLink[n], index[to])
+			}
+		}
+
+		if lowLink[n] == index[n] {
+			component := make([]N, 0, 1)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack.Remove(top)
+				component = append(component, top)
+				if top == n {
+					break
+				}
+			}
+			g.sortNodes(component)
+			result = append(result, component)
+		}
+	}
+
+	for _, n := range g.Nodes() {
+		if _, visited := index[n]; !visited {
+			connect(n)
+		}
+	}
+	return result
+}
+
+// TransitiveReduction returns a new graph with the same reachability as this graph but with the
+// fewest possible edges, i.e. an edge a -> c is removed when a -> b -> c also exists.
+// A [*CycleError] is returned if the graph contains a cycle.
+func (g *Graph[N]) TransitiveReduction() (*Graph[N], error) {
+	if _, err := g.TopologicalSort(); err != nil {
+		return nil, err
+	}
+
+	reduced := g.Clone()
+	for _, from := range g.Nodes() {
+		successors := g.Successors(from)
+		for _, via := range successors {
+			reachable := g.Reachable(via)
+			for _, to := range successors {
+				if to != via && reachable.Contains(to) {
+					reduced.RemoveEdge(from, to)
+				}
+			}
+		}
+	}
+	return reduced, nil
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+// Nodes are labelled using fmt.Sprint.
+// See https://graphviz.org/doc/info/lang.html for the DOT language.
+func (g *Graph[N]) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph {")
+	for _, n := range g.Nodes() {
+		if g.out[n].Len() == 0 && g.in[n].Len() == 0 {
+			fmt.Fprintf(bw, "\t%s;\n", strconv.Quote(fmt.Sprint(n)))
+		}
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(bw, "\t%s -> %s;\n", strconv.Quote(fmt.Sprint(e.First)), strconv.Quote(fmt.Sprint(e.Second)))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+//-----------------------------------------------------------------------------
+
+// sortNodes sorts the nodes in the order they were added to the graph.
+func (g *Graph[N]) sortNodes(nodes []N) {
+	slices.SortFunc(nodes, func(a N, b N) int {
+		return g.nodes[a] - g.nodes[b]
+	})
+}
+
+func (g *Graph[N]) sortedItems(s Set[N]) []N {
+	if s.items == nil {
+		return []N{}
+	}
+	items := s.Items()
+	g.sortNodes(items)
+	return items
+}
+
+// findCycle returns a cycle from the nodes that could not be sorted topologically
+// (those with a remaining in-degree > 0).
+func (g *Graph[N]) findCycle(inDegree map[N]int) []N {
+	// Every remaining node has a remaining predecessor, so walking backwards must eventually repeat a node
+	var start N
+	for _, n := range g.Nodes() {
+		if inDegree[n] > 0 {
+			start = n
+			break
+		}
+	}
+
+	position := make(map[N]int)
+	path := make([]N, 0)
+	n := start
+	for {
+		if i, seen := position[n]; seen {
+			cycle := slices.Clone(path[i:])
+			slices.Reverse(cycle)
+			// Start the cycle at the node that was added to the graph first
+			first := 0
+			for j := range cycle {
+				if g.nodes[cycle[j]] < g.nodes[cycle[first]] {
+					first = j
+				}
+			}
+			cycle = slices.Concat(cycle[first:], cycle[:first])
+			return append(cycle, cycle[0])
+		}
+		position[n] = len(path)
+		path = append(path, n)
+		for _, from := range g.Predecessors(n) {
+			if inDegree[from] > 0 {
+				n = from
+				break
+			}
+		}
+	}
+}
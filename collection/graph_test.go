@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphNodesAndEdges(t *testing.T) {
+	g := collection.NewGraph[string]()
+	assert.True(t, g.AddNode("a"))
+	assert.False(t, g.AddNode("a"))
+	assert.True(t, g.AddEdge("a", "b"))
+	assert.True(t, g.AddEdge("a", "c"))
+	assert.True(t, g.AddEdge("c", "b"))
+	assert.False(t, g.AddEdge("a", "b"))
+
+	assert.Equal(t, 3, g.Len())
+	assert.Equal(t, 3, g.EdgeCount())
+	assert.Equal(t, []string{"a", "b", "c"}, g.Nodes())
+	assert.Equal(t, []string{"b", "c"}, g.Successors("a"))
+	assert.Equal(t, []string{"a", "c"}, g.Predecessors("b"))
+	assert.Equal(t, []string{}, g.Successors("missing"))
+	assert.Equal(t, 2, g.OutDegree("a"))
+	assert.Equal(t, 2, g.InDegree("b"))
+	assert.True(t, g.HasEdge("c", "b"))
+	assert.False(t, g.HasEdge("b", "c"))
+
+	assert.Equal(t, []collection.Pair[string, string]{
+		{First: "a", Second: "b"}, {First: "a", Second: "c"}, {First: "c", Second: "b"},
+	}, g.Edges())
+
+	assert.True(t, g.RemoveEdge("a", "b"))
+	assert.False(t, g.RemoveEdge("a", "b"))
+	assert.Equal(t, 2, g.EdgeCount())
+
+	assert.True(t, g.RemoveNode("c"))
+	assert.False(t, g.RemoveNode("c"))
+	assert.Equal(t, 0, g.EdgeCount())
+	assert.Equal(t, []string{"a", "b"}, g.Nodes())
+}
+
+func TestGraphTopologicalSort(t *testing.T) {
+	g := collection.NewGraph[string]()
+	g.AddEdge("app", "lib")
+	g.AddEdge("app", "log")
+	g.AddEdge("lib", "log")
+	g.AddEdge("lib", "fmt")
+	g.AddEdge("log", "fmt")
+	g.AddNode("standalone")
+
+	order, err := g.TopologicalSort()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "standalone", "lib", "log", "fmt"}, order)
+	assert.True(t, g.IsAcyclic())
+
+	g.AddEdge("fmt", "lib")
+	_, err = g.TopologicalSort()
+	require.Error(t, err)
+	assert.False(t, g.IsAcyclic())
+
+	var cycleErr *collection.CycleError[string]
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, []string{"lib", "fmt", "lib"}, cycleErr.Cycle)
+	assert.Equal(t, "graph contains a cycle: lib -> fmt -> lib", err.Error())
+
+	self := collection.NewGraph[int]()
+	self.AddEdge(1, 1)
+	_, err = self.TopologicalSort()
+	require.True(t, errors.As(err, new(*collection.CycleError[int])))
+}
+
+func TestGraphReachable(t *testing.T) {
+	g := collection.NewGraph[int]()
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(5, 1)
+
+	items := g.Reachable(1).Items()
+	slices.Sort(items)
+	assert.Equal(t, []int{2, 3, 4}, items)
+	assert.Equal(t, 0, g.Reachable(4).Len())
+	assert.Equal(t, 0, g.Reachable(42).Len())
+
+	g.AddEdge(4, 1)
+	assert.True(t, g.Reachable(1).Contains(1))
+}
+
+func TestGraphStronglyConnectedComponents(t *testing.T) {
+	g := collection.NewGraph[string]()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "a")
+	g.AddEdge("b", "d")
+	g.AddEdge("d", "e")
+	g.AddEdge("e", "d")
+	g.AddEdge("e", "f")
+
+	assert.Equal(t, [][]string{{"f"}, {"d", "e"}, {"a", "b", "c"}}, g.StronglyConnectedComponents())
+}
+
+func TestGraphTransitiveReduction(t *testing.T) {
+	g := collection.NewGraph[string]()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("a", "c")
+	g.AddEdge("c", "d")
+	g.AddEdge("a", "d")
+	g.AddEdge("b", "d")
+
+	reduced, err := g.TransitiveReduction()
+	require.NoError(t, err)
+	assert.Equal(t, []collection.Pair[string, string]{
+		{First: "a", Second: "b"}, {First: "b", Second: "c"}, {First: "c", Second: "d"},
+	}, reduced.Edges())
+	assert.Equal(t, 6, g.EdgeCount())
+
+	g.AddEdge("d", "a")
+	_, err = g.TransitiveReduction()
+	assert.Error(t, err)
+}
+
+func TestGraphWriteDOT(t *testing.T) {
+	g := collection.NewGraph[string]()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", `say "hi"`)
+	g.AddNode("lonely")
+
+	var sb strings.Builder
+	require.NoError(t, g.WriteDOT(&sb))
+	assert.Equal(t, "digraph {\n"+
+		"\t\"lonely\";\n"+
+		"\t\"a\" -> \"b\";\n"+
+		"\t\"b\" -> \"say \\\"hi\\\"\";\n"+
+		"}\n", sb.String())
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"slices"
+	"sync/atomic"
+)
+
+// See https://en.wikipedia.org/wiki/Hash_array_mapped_trie for the data structure.
+
+const (
+	hamtBits     = 5
+	hamtMask     = 1<<hamtBits - 1
+	hamtMaxShift = 64
+)
+
+// hamtSeed is used to hash all keys stored in a HAMT.
+var hamtSeed = maphash.MakeSeed()
+
+// hamtGeneration is used to hand out unique generations to builders.
+// Nodes with generation 0 are never modified in place.
+var hamtGeneration atomic.Uint64
+
+// hamtNode is either a bitmap indexed node where each set bit in the bitmap corresponds to an entry,
+// or a collision node (once all the bits of the hash have been used) that stores the entries in a list.
+type hamtNode[K comparable, V any] struct {
+	gen       uint64
+	bitmap    uint32
+	collision bool
+	entries   []hamtEntry[K, V]
+}
+
+// hamtEntry is either a key-value pair or a sub-trie when child is not nil.
+type hamtEntry[K comparable, V any] struct {
+	child *hamtNode[K, V]
+	hash  uint64
+	key   K
+	value V
+}
+
+// hamtHash returns the hash of the key.
+// maphash.Comparable is the reason this module requires Go 1.24 or later.
+func hamtHash[K comparable](key K) uint64 {
+	return maphash.Comparable(hamtSeed, key)
+}
+
+// editable returns n if it may be modified in place by the generation, otherwise a copy of n.
+func (n *hamtNode[K, V]) editable(gen uint64) *hamtNode[K, V] {
+	if gen != 0 && n.gen == gen {
+		return n
+	}
+	return &hamtNode[K, V]{
+		gen:       gen,
+		bitmap:    n.bitmap,
+		collision: n.collision,
+		entries:   slices.Clone(n.entries),
+	}
+}
+
+// index returns the position of the entry for the hash and whether the entry exists.
+func (n *hamtNode[K, V]) index(shift uint, hash uint64) (uint32, int, bool) {
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1)), n.bitmap&bit != 0
+}
+
+func (n *hamtNode[K, V]) get(shift uint, hash uint64, key K) (V, bool) {
+	for {
+		if n.collision {
+			for _, e := range n.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			break
+		}
+
+		_, i, exists := n.index(shift, hash)
+		if !exists {
+			break
+		}
+		e := &n.entries[i]
+		if e.child == nil {
+			if e.hash == hash && e.key == key {
+				return e.value, true
+			}
+			break
+		}
+		n = e.child
+		shift += hamtBits
+	}
+
+	var zero V
+	return zero, false
+}
+
+// set returns the node with the key-value pair stored and true if the key is new.
+func (n *hamtNode[K, V]) set(gen uint64, shift uint, hash uint64, key K, value V) (*hamtNode[K, V], bool) {
+	entry := hamtEntry[K, V]{hash: hash, key: key, value: value}
+
+	if n.collision {
+		m := n.editable(gen)
+		for i := range m.entries {
+			if m.entries[i].key == key {
+				m.entries[i].value = value
+				return m, false
+			}
+		}
+		m.entries = append(m.entries, entry)
+		return m, true
+	}
+
+	bit, i, exists := n.index(shift, hash)
+	if !exists {
+		m := n.editable(gen)
+		m.bitmap |= bit
+		m.entries = slices.Insert(m.entries, i, entry)
+		return m, true
+	}
+
+	e := n.entries[i]
+	m := n.editable(gen)
+	switch {
+	case e.child != nil:
+		child, added := e.child.set(gen, shift+hamtBits, hash, key, value)
+		m.entries[i].child = child
+		return m, added
+	case e.hash == hash && e.key == key:
+		m.entries[i].value = value
+		return m, false
+	default:
+		m.entries[i] = hamtEntry[K, V]{child: newHamtPair(gen, shift+hamtBits, e, entry)}
+		return m, true
+	}
+}
+
+// newHamtPair creates a new node that contains the two entries with different keys.
+func newHamtPair[K comparable, V any](gen uint64, shift uint, a hamtEntry[K, V], b hamtEntry[K, V]) *hamtNode[K, V] {
+	if shift >= hamtMaxShift {
+		return &hamtNode[K, V]{gen: gen, collision: true, entries: []hamtEntry[K, V]{a, b}}
+	}
+
+	ia := (a.hash >> shift) & hamtMask
+	ib := (b.hash >> shift) & hamtMask
+	if ia == ib {
+		return &hamtNode[K, V]{
+			gen:     gen,
+			bitmap:  1 << ia,
+			entries: []hamtEntry[K, V]{{child: newHamtPair(gen, shift+hamtBits, a, b)}},
+		}
+	}
+	if ia > ib {
+		a, b = b, a
+	}
+	return &hamtNode[K, V]{gen: gen, bitmap: 1<<ia | 1<<ib, entries: []hamtEntry[K, V]{a, b}}
+}
+
+// delete returns the node with the key removed and true if the key existed.
+// The node returned might be empty or contain only a single key-value pair which the parent should inline.
+func (n *hamtNode[K, V]) delete(gen uint64, shift uint, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if n.collision {
+		for i := range n.entries {
+			if n.entries[i].key == key {
+				m := n.editable(gen)
+				m.entries = slices.Delete(m.entries, i, i+1)
+				return m, true
+			}
+		}
+		return n, false
+	}
+
+	bit, i, exists := n.index(shift, hash)
+	if !exists {
+		return n, false
+	}
+
+	e := n.entries[i]
+	if e.child == nil {
+		if e.hash != hash || e.key != key {
+			return n, false
+		}
+		m := n.editable(gen)
+		m.bitmap &^= bit
+		m.entries = slices.Delete(m.entries, i, i+1)
+		return m, true
+	}
+
+	child, removed := e.child.delete(gen, shift+hamtBits, hash, key)
+	if !removed {
+		return n, false
+	}
+
+	m := n.editable(gen)
+	switch {
+	case len(child.entries) == 0:
+		m.bitmap &^= bit
+		m.entries = slices.Delete(m.entries, i, i+1)
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// Pull the only remaining key-value pair up into this node
+		m.entries[i] = child.entries[0]
+	default:
+		m.entries[i].child = child
+	}
+	return m, true
+}
+
+// walk visits all the key-value pairs. Returns false if the walk was stopped.
+func (n *hamtNode[K, V]) walk(yield func(K, V) bool) bool {
+	for i := range n.entries {
+		e := &n.entries[i]
+		if e.child != nil {
+			if !e.child.walk(yield) {
+				return false
+			}
+		} else if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
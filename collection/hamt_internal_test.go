@@ -0,0 +1,71 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Real hashes practically never collide, so force collisions by using the same hash for different keys.
+func TestHamtCollisions(t *testing.T) {
+	const hash = 0xdeadbeef
+	n := &hamtNode[string, int]{}
+
+	n, added := n.set(0, 0, hash, "a", 1)
+	assert.True(t, added)
+	n, added = n.set(0, 0, hash, "b", 2)
+	assert.True(t, added)
+	n, added = n.set(0, 0, hash, "c", 3)
+	assert.True(t, added)
+	n, added = n.set(0, 0, hash, "b", 20)
+	assert.False(t, added)
+
+	v, ok := n.get(0, hash, "b")
+	assert.True(t, ok)
+	assert.Equal(t, 20, v)
+	_, ok = n.get(0, hash, "d")
+	assert.False(t, ok)
+
+	before := n
+	n, removed := n.delete(0, 0, hash, "a")
+	assert.True(t, removed)
+	_, removed = n.delete(0, 0, hash, "a")
+	assert.False(t, removed)
+	n, removed = n.delete(0, 0, hash, "c")
+	assert.True(t, removed)
+
+	// The last remaining pair is pulled back up to the root
+	assert.False(t, n.collision)
+	assert.Len(t, n.entries, 1)
+	assert.Nil(t, n.entries[0].child)
+	v, ok = n.get(0, hash, "b")
+	assert.True(t, ok)
+	assert.Equal(t, 20, v)
+
+	// The previous version still has all of the keys
+	count := 0
+	before.walk(func(string, int) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 3, count)
+}
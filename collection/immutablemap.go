@@ -0,0 +1,191 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import "iter"
+
+// ImmutableMap is a persistent map where [ImmutableMap.Set] and [ImmutableMap.Delete] return a new
+// version of the map in O(log32 n) time while leaving the original unchanged.
+// The versions share most of their structure, which makes it cheap to keep many versions around and
+// safe to share a map between goroutines without copying or locking.
+// The map is implemented as a hash array mapped trie (HAMT) and iteration order is not specified.
+// The zero value is an empty map ready to use.
+type ImmutableMap[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	len  int
+}
+
+// NewImmutableMap creates a new empty immutable map.
+func NewImmutableMap[K comparable, V any]() ImmutableMap[K, V] {
+	return ImmutableMap[K, V]{}
+}
+
+// NewImmutableMapFrom creates a new immutable map that contains all the key-value pairs from m.
+func NewImmutableMapFrom[K comparable, V any](m map[K]V) ImmutableMap[K, V] {
+	b := NewImmutableMapBuilder[K, V]()
+	for k, v := range m {
+		b.Set(k, v)
+	}
+	return b.Map()
+}
+
+// Len returns the number of key-value pairs.
+func (m ImmutableMap[K, V]) Len() int {
+	return m.len
+}
+
+// Get returns the value for the key and true if the key exists.
+func (m ImmutableMap[K, V]) Get(key K) (V, bool) {
+	if m.root == nil {
+		var zero V
+		return zero, false
+	}
+	return m.root.get(0, hamtHash(key), key)
+}
+
+// Contains returns true if the key exists.
+func (m ImmutableMap[K, V]) Contains(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Set returns a new map with the value stored for the key.
+func (m ImmutableMap[K, V]) Set(key K, value V) ImmutableMap[K, V] {
+	root, added := m.root.orEmpty().set(0, 0, hamtHash(key), key, value)
+	result := ImmutableMap[K, V]{root: root, len: m.len}
+	if added {
+		result.len++
+	}
+	return result
+}
+
+// Delete returns a new map without the key. The same map is returned if the key does not exist.
+func (m ImmutableMap[K, V]) Delete(key K) ImmutableMap[K, V] {
+	if m.root == nil {
+		return m
+	}
+	root, removed := m.root.delete(0, 0, hamtHash(key), key)
+	if !removed {
+		return m
+	}
+	return ImmutableMap[K, V]{root: root, len: m.len - 1}
+}
+
+// All returns an iterator over all the key-value pairs.
+func (m ImmutableMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.root != nil {
+			m.root.walk(yield)
+		}
+	}
+}
+
+// Keys returns all the keys.
+func (m ImmutableMap[K, V]) Keys() []K {
+	result := make([]K, 0, m.len)
+	for k := range m.All() {
+		result = append(result, k)
+	}
+	return result
+}
+
+// ToMap returns a new Go map that contains all the key-value pairs.
+func (m ImmutableMap[K, V]) ToMap() map[K]V {
+	result := make(map[K]V, m.len)
+	for k, v := range m.All() {
+		result[k] = v
+	}
+	return result
+}
+
+// Builder returns a builder that starts with the key-value pairs of this map.
+// The map itself is not affected by changes made using the builder.
+func (m ImmutableMap[K, V]) Builder() *ImmutableMapBuilder[K, V] {
+	return &ImmutableMapBuilder[K, V]{
+		m:   m,
+		gen: hamtGeneration.Add(1),
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// ImmutableMapBuilder is used to efficiently make many changes to an [ImmutableMap].
+// Unlike the map itself, the builder modifies the nodes it created in place instead of copying them.
+// A builder is not safe for concurrent use.
+type ImmutableMapBuilder[K comparable, V any] struct {
+	m   ImmutableMap[K, V]
+	gen uint64
+}
+
+// NewImmutableMapBuilder creates a new builder for an empty map.
+func NewImmutableMapBuilder[K comparable, V any]() *ImmutableMapBuilder[K, V] {
+	return ImmutableMap[K, V]{}.Builder()
+}
+
+// Len returns the number of key-value pairs.
+func (b *ImmutableMapBuilder[K, V]) Len() int {
+	return b.m.len
+}
+
+// Get returns the value for the key and true if the key exists.
+func (b *ImmutableMapBuilder[K, V]) Get(key K) (V, bool) {
+	return b.m.Get(key)
+}
+
+// Set stores the value for the key.
+// Returns true if the key is new and false if an existing value was replaced.
+func (b *ImmutableMapBuilder[K, V]) Set(key K, value V) bool {
+	root, added := b.m.root.orEmpty().set(b.gen, 0, hamtHash(key), key, value)
+	b.m.root = root
+	if added {
+		b.m.len++
+	}
+	return added
+}
+
+// Delete removes the key.
+// Returns true if the key existed before being removed.
+func (b *ImmutableMapBuilder[K, V]) Delete(key K) bool {
+	if b.m.root == nil {
+		return false
+	}
+	root, removed := b.m.root.delete(b.gen, 0, hamtHash(key), key)
+	if removed {
+		b.m.root = root
+		b.m.len--
+	}
+	return removed
+}
+
+// Map returns the immutable map with all the changes made so far.
+// The builder can continue to be used afterwards without affecting the map returned.
+func (b *ImmutableMapBuilder[K, V]) Map() ImmutableMap[K, V] {
+	// The nodes are now shared with the map and may no longer be modified in place
+	b.gen = hamtGeneration.Add(1)
+	return b.m
+}
+
+// orEmpty returns n or a new empty node if n is nil.
+func (n *hamtNode[K, V]) orEmpty() *hamtNode[K, V] {
+	if n == nil {
+		return &hamtNode[K, V]{}
+	}
+	return n
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableMapSetGetDelete(t *testing.T) {
+	var empty collection.ImmutableMap[string, int]
+	assert.Equal(t, 0, empty.Len())
+	assert.False(t, empty.Contains("a"))
+	assert.Equal(t, empty, empty.Delete("a"))
+
+	m1 := empty.Set("a", 1)
+	m2 := m1.Set("b", 2)
+	m3 := m2.Set("a", 10)
+	m4 := m3.Delete("b")
+
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, map[string]int{"a": 1}, m1.ToMap())
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, m2.ToMap())
+	assert.Equal(t, map[string]int{"a": 10, "b": 2}, m3.ToMap())
+	assert.Equal(t, map[string]int{"a": 10}, m4.ToMap())
+
+	v, ok := m3.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	_, ok = m4.Get("b")
+	assert.False(t, ok)
+
+	assert.Equal(t, 2, m3.Len())
+	assert.Equal(t, 1, m4.Delete("z").Len())
+	assert.Equal(t, 0, m4.Delete("a").Len())
+	assert.ElementsMatch(t, []string{"a", "b"}, m2.Keys())
+}
+
+func TestImmutableMapFrom(t *testing.T) {
+	src := make(map[int]string)
+	for i := 0; i < 1000; i++ {
+		src[i] = string(rune('a' + i%26))
+	}
+	m := collection.NewImmutableMapFrom(src)
+	assert.Equal(t, 1000, m.Len())
+	assert.Equal(t, src, m.ToMap())
+	assert.Equal(t, src, maps.Collect(m.All()))
+
+	count := 0
+	for range m.All() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, count)
+}
+
+func TestImmutableMapModel(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	m := collection.NewImmutableMap[int, int]()
+	model := make(map[int]int)
+
+	// Keep some old versions around to check that they are never modified
+	type version struct {
+		m     collection.ImmutableMap[int, int]
+		model map[int]int
+	}
+	var versions []version
+
+	for i := 0; i < 20000; i++ {
+		key := rnd.IntN(2000)
+		if rnd.IntN(3) == 0 {
+			_, existed := model[key]
+			delete(model, key)
+			next := m.Delete(key)
+			assert.Equal(t, existed, next.Len() != m.Len())
+			m = next
+		} else {
+			model[key] = i
+			m = m.Set(key, i)
+		}
+		require.Equal(t, len(model), m.Len())
+
+		if i%1000 == 0 {
+			versions = append(versions, version{m: m, model: maps.Clone(model)})
+		}
+	}
+
+	assert.Equal(t, model, m.ToMap())
+	for _, v := range versions {
+		assert.Equal(t, v.model, v.m.ToMap())
+	}
+}
+
+func TestImmutableMapBuilder(t *testing.T) {
+	base := collection.NewImmutableMap[int, int]().Set(1, 1).Set(2, 2)
+
+	b := base.Builder()
+	assert.Equal(t, 2, b.Len())
+	for i := 0; i < 100; i++ {
+		b.Set(i, i*10)
+	}
+	assert.False(t, b.Set(5, 5))
+	assert.True(t, b.Delete(2))
+	assert.False(t, b.Delete(2))
+	assert.Equal(t, 99, b.Len())
+	v, ok := b.Get(5)
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+
+	m1 := b.Map()
+	assert.Equal(t, 99, m1.Len())
+
+	// Changes made after building must not affect the map already returned
+	for i := 0; i < 100; i++ {
+		b.Delete(i)
+	}
+	b.Set(1000, 1)
+	m2 := b.Map()
+
+	assert.Equal(t, map[int]int{1: 1, 2: 2}, base.ToMap())
+	assert.Equal(t, 99, m1.Len())
+	v, ok = m1.Get(50)
+	assert.True(t, ok)
+	assert.Equal(t, 500, v)
+	assert.Equal(t, map[int]int{1000: 1}, m2.ToMap())
+
+	empty := collection.NewImmutableMapBuilder[string, int]()
+	assert.False(t, empty.Delete("a"))
+	assert.Equal(t, 0, empty.Map().Len())
+}
+
+func TestImmutableMapConcurrentReads(t *testing.T) {
+	m := collection.NewImmutableMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		m = m.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				v, ok := m.Get(i)
+				assert.True(t, ok)
+				assert.Equal(t, i, v)
+			}
+			assert.Equal(t, 1000, len(slices.Collect(maps.Keys(m.ToMap()))))
+		}()
+	}
+
+	// Writers create new versions without affecting the readers
+	next := m
+	for i := 0; i < 1000; i++ {
+		next = next.Delete(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 0, next.Len())
+	assert.Equal(t, 1000, m.Len())
+}
+
+func BenchmarkImmutableMap(b *testing.B) {
+	const items = 10000
+
+	b.Run("Set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := collection.NewImmutableMap[int, int]()
+			for j := 0; j < items; j++ {
+				m = m.Set(j, j)
+			}
+		}
+	})
+
+	b.Run("Builder", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			builder := collection.NewImmutableMapBuilder[int, int]()
+			for j := 0; j < items; j++ {
+				builder.Set(j, j)
+			}
+			_ = builder.Map()
+		}
+	})
+
+	b.Run("Get", func(b *testing.B) {
+		builder := collection.NewImmutableMapBuilder[int, int]()
+		for j := 0; j < items; j++ {
+			builder.Set(j, j)
+		}
+		m := builder.Map()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = m.Get(i % items)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import "iter"
+
+// ImmutableSet is a persistent set where [ImmutableSet.With] and [ImmutableSet.Without] return a new
+// version of the set in O(log32 n) time while leaving the original unchanged.
+// Deriving many slightly different sets from a large base set is cheap because the versions share
+// most of their structure, and sets can be shared between goroutines without copying or locking.
+// The set is backed by an [ImmutableMap] and iteration order is not specified.
+// The zero value is an empty set ready to use.
+type ImmutableSet[T comparable] struct {
+	m ImmutableMap[T, struct{}]
+}
+
+// NewImmutableSet creates a new immutable set that contains the unique items.
+func NewImmutableSet[T comparable](items ...T) ImmutableSet[T] {
+	b := NewImmutableMapBuilder[T, struct{}]()
+	for _, item := range items {
+		b.Set(item, struct{}{})
+	}
+	return ImmutableSet[T]{m: b.Map()}
+}
+
+// NewImmutableSetFrom creates a new immutable set that contains the items of the set s.
+func NewImmutableSetFrom[T comparable](s Set[T]) ImmutableSet[T] {
+	return NewImmutableSet(s.Items()...)
+}
+
+// Len returns the number of items stored in the set.
+func (s ImmutableSet[T]) Len() int {
+	return s.m.Len()
+}
+
+// Contains returns true if the item is in the set.
+func (s ImmutableSet[T]) Contains(item T) bool {
+	return s.m.Contains(item)
+}
+
+// With returns a new set that also contains the item.
+func (s ImmutableSet[T]) With(item T) ImmutableSet[T] {
+	if s.Contains(item) {
+		return s
+	}
+	return ImmutableSet[T]{m: s.m.Set(item, struct{}{})}
+}
+
+// Without returns a new set that does not contain the item.
+func (s ImmutableSet[T]) Without(item T) ImmutableSet[T] {
+	return ImmutableSet[T]{m: s.m.Delete(item)}
+}
+
+// Items returns the items stored in the set.
+func (s ImmutableSet[T]) Items() []T {
+	return s.m.Keys()
+}
+
+// All returns an iterator over all the items.
+func (s ImmutableSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range s.m.All() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// ToSet returns a new mutable [Set] that contains all the items.
+func (s ImmutableSet[T]) ToSet() Set[T] {
+	result := NewSetWithCapacity[T](s.Len())
+	for item := range s.All() {
+		result.Insert(item)
+	}
+	return result
+}
+
+// Equal returns true if both sets contain exactly the same items.
+func (a ImmutableSet[T]) Equal(b ImmutableSet[T]) bool {
+	if a.m.root == b.m.root {
+		return true
+	}
+	return a.Len() == b.Len() && a.IsSubsetOf(b)
+}
+
+// IsSubsetOf returns true if every item in this set is also in b.
+func (a ImmutableSet[T]) IsSubsetOf(b ImmutableSet[T]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	for item := range a.All() {
+		if !b.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return a new set that is the union of this set and another.
+// The result shares its structure with the larger of the two sets.
+func (a ImmutableSet[T]) Union(b ImmutableSet[T]) ImmutableSet[T] {
+	if a.Len() < b.Len() {
+		a, b = b, a
+	}
+	builder := a.m.Builder()
+	for item := range b.All() {
+		builder.Set(item, struct{}{})
+	}
+	return ImmutableSet[T]{m: builder.Map()}
+}
+
+// Return a new set that contains only the items that are present in both sets.
+// The result shares its structure with the smaller of the two sets.
+func (a ImmutableSet[T]) Intersection(b ImmutableSet[T]) ImmutableSet[T] {
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	builder := a.m.Builder()
+	for item := range a.All() {
+		if !b.Contains(item) {
+			builder.Delete(item)
+		}
+	}
+	return ImmutableSet[T]{m: builder.Map()}
+}
+
+// Return a new set that contains only the items that are present in this set but not in b.
+// The result shares its structure with this set.
+func (a ImmutableSet[T]) Difference(b ImmutableSet[T]) ImmutableSet[T] {
+	builder := a.m.Builder()
+	if b.Len() < a.Len() {
+		for item := range b.All() {
+			builder.Delete(item)
+		}
+	} else {
+		for item := range a.All() {
+			if b.Contains(item) {
+				builder.Delete(item)
+			}
+		}
+	}
+	return ImmutableSet[T]{m: builder.Map()}
+}
+
+// Return a new set that contains only the items that are present in one or the other set but not the items that appear in both sets.
+// The result shares its structure with the larger of the two sets.
+func (a ImmutableSet[T]) SymmetricDifference(b ImmutableSet[T]) ImmutableSet[T] {
+	if a.Len() < b.Len() {
+		a, b = b, a
+	}
+	builder := a.m.Builder()
+	for item := range b.All() {
+		if !builder.Delete(item) {
+			builder.Set(item, struct{}{})
+		}
+	}
+	return ImmutableSet[T]{m: builder.Map()}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableSetWithWithout(t *testing.T) {
+	var empty collection.ImmutableSet[string]
+	assert.Equal(t, 0, empty.Len())
+
+	s1 := empty.With("apple").With("pear")
+	s2 := s1.With("kiwi").With("apple")
+	s3 := s2.Without("pear").Without("banana")
+
+	assert.Equal(t, 0, empty.Len())
+	assert.ElementsMatch(t, []string{"apple", "pear"}, s1.Items())
+	assert.ElementsMatch(t, []string{"apple", "pear", "kiwi"}, s2.Items())
+	assert.ElementsMatch(t, []string{"apple", "kiwi"}, slices.Collect(s3.All()))
+	assert.True(t, s3.Contains("kiwi"))
+	assert.False(t, s3.Contains("pear"))
+
+	assert.True(t, s1.Equal(s1.With("pear")))
+	assert.True(t, s3.Equal(collection.NewImmutableSet("kiwi", "apple")))
+	assert.False(t, s3.Equal(s1))
+	assert.True(t, s3.IsSubsetOf(s2))
+	assert.False(t, s2.IsSubsetOf(s3))
+}
+
+func TestImmutableSetConversion(t *testing.T) {
+	s := collection.NewSetFrom([]int{5, 9, 3, 42, 3})
+	is := collection.NewImmutableSetFrom(s)
+	assert.Equal(t, 4, is.Len())
+	assert.Equal(t, s, is.ToSet())
+
+	// Changing the mutable set must not affect the immutable set
+	s.Insert(100)
+	assert.False(t, is.Contains(100))
+}
+
+func TestImmutableSetAlgebra(t *testing.T) {
+	a := collection.NewImmutableSet(1, 2, 3, 4)
+	b := collection.NewImmutableSet(3, 4, 5)
+
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5}, a.Union(b).Items())
+	assert.ElementsMatch(t, []int{3, 4}, a.Intersection(b).Items())
+	assert.ElementsMatch(t, []int{1, 2}, a.Difference(b).Items())
+	assert.ElementsMatch(t, []int{5}, b.Difference(a).Items())
+	assert.ElementsMatch(t, []int{1, 2, 5}, a.SymmetricDifference(b).Items())
+
+	// The inputs are never modified
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, a.Items())
+	assert.ElementsMatch(t, []int{3, 4, 5}, b.Items())
+
+	var empty collection.ImmutableSet[int]
+	assert.True(t, a.Union(empty).Equal(a))
+	assert.Equal(t, 0, a.Intersection(empty).Len())
+	assert.True(t, a.Difference(empty).Equal(a))
+	assert.True(t, empty.SymmetricDifference(a).Equal(a))
+}
+
+func TestImmutableSetAlgebraMatchesSet(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	randomItems := func(n int) []int {
+		items := make([]int, n)
+		for i := range items {
+			items[i] = rnd.IntN(500)
+		}
+		return items
+	}
+
+	for i := 0; i < 20; i++ {
+		as := randomItems(rnd.IntN(400))
+		bs := randomItems(rnd.IntN(400))
+		a, b := collection.NewSetFrom(as), collection.NewSetFrom(bs)
+		ia, ib := collection.NewImmutableSet(as...), collection.NewImmutableSet(bs...)
+
+		require.Equal(t, a.Union(b), ia.Union(ib).ToSet())
+		require.Equal(t, a.Intersection(b), ia.Intersection(ib).ToSet())
+		require.Equal(t, a.Difference(b), ia.Difference(ib).ToSet())
+		require.Equal(t, b.Difference(a), ib.Difference(ia).ToSet())
+		require.Equal(t, a.SymmetricDifference(b), ia.SymmetricDifference(ib).ToSet())
+		require.Equal(t, a, ia.ToSet())
+		require.Equal(t, b, ib.ToSet())
+	}
+}
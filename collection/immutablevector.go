@@ -0,0 +1,482 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"iter"
+	"slices"
+)
+
+// See https://hypirion.com/musings/understanding-persistent-vector-pt-1 for the data structure.
+
+const (
+	vectorBits  = 5
+	vectorWidth = 1 << vectorBits
+	vectorMask  = vectorWidth - 1
+)
+
+// ImmutableVector is a persistent list where [ImmutableVector.Append] and [ImmutableVector.Set]
+// return a new version of the vector in O(log32 n) time while leaving the original unchanged.
+// The versions share most of their structure, which makes it cheap to keep many versions around and
+// safe to share a vector between goroutines without copying or locking.
+// The items are stored in a 32-way trie with the last (up to) 32 items kept in a separate tail
+// which makes appending very cheap.
+// The zero value is an empty vector ready to use.
+type ImmutableVector[T any] struct {
+	root   *vectorNode[T]
+	tail   *vectorNode[T] // leaf that is not yet part of the trie
+	offset int            // position of the first item, the positions before it are not used
+	len    int
+	shift  uint
+}
+
+// vectorNode is either an internal node with children or a leaf with exactly 32 values.
+// Children that only contain positions before the offset of a vector may be nil.
+type vectorNode[T any] struct {
+	gen      uint64
+	children []*vectorNode[T]
+	values   []T
+}
+
+// NewImmutableVector creates a new vector that contains the items.
+func NewImmutableVector[T any](items ...T) ImmutableVector[T] {
+	b := NewImmutableVectorBuilder[T]()
+	for _, item := range items {
+		b.Append(item)
+	}
+	return b.Vector()
+}
+
+// NewImmutableVectorFromSeq creates a new vector that contains the items from the iterator.
+func NewImmutableVectorFromSeq[T any](seq iter.Seq[T]) ImmutableVector[T] {
+	b := NewImmutableVectorBuilder[T]()
+	for item := range seq {
+		b.Append(item)
+	}
+	return b.Vector()
+}
+
+// Len returns the number of items.
+func (v ImmutableVector[T]) Len() int {
+	return v.len
+}
+
+// Get returns the item at index i.
+// Panics if i is out of range.
+func (v ImmutableVector[T]) Get(i int) T {
+	checkIndex(i, v.len)
+	return v.get(v.offset + i)
+}
+
+// Append returns a new vector with the items added to the end.
+func (v ImmutableVector[T]) Append(items ...T) ImmutableVector[T] {
+	if len(items) == 1 {
+		return v.append(0, items[0])
+	}
+	b := v.Builder()
+	for _, item := range items {
+		b.Append(item)
+	}
+	return b.Vector()
+}
+
+// Set returns a new vector with the item at index i replaced.
+// Panics if i is out of range.
+func (v ImmutableVector[T]) Set(i int, item T) ImmutableVector[T] {
+	return v.set(0, i, item)
+}
+
+// Slice returns a new vector that contains the items in the range [lo, hi).
+// The result shares its structure with v and runs in O(log32 n) time.
+// Panics if the range is invalid.
+func (v ImmutableVector[T]) Slice(lo int, hi int) ImmutableVector[T] {
+	checkRange(lo, hi, v.len)
+	switch {
+	case lo == 0 && hi == v.len:
+		return v
+	case lo == hi:
+		return ImmutableVector[T]{}
+	case hi < v.len:
+		v = v.truncate(hi)
+	}
+
+	v.offset += lo
+	v.len -= lo
+	return v.trimFront()
+}
+
+// Concat returns a new vector with the items of other added to the end.
+// The result shares its structure with v. When the items of other are at the same position within
+// their leaves of 32 items as they will be in the result (e.g. v.Len() is a multiple of 32), then the
+// leaves of other are shared too and it runs in O(m/32 log32 n) time where m is the length of other.
+// Otherwise the items of other are copied in O(m log32 n) time.
+func (v ImmutableVector[T]) Concat(other ImmutableVector[T]) ImmutableVector[T] {
+	switch {
+	case v.len == 0:
+		return other
+	case other.len == 0:
+		return v
+	}
+
+	b := v.Builder()
+	i := 0
+	if (v.end()-other.offset)&vectorMask == 0 {
+		// Copy the items up to the first leaf boundary after which whole leaves can be shared
+		for ; i < other.len && (other.offset+i)&vectorMask != 0; i++ {
+			b.Append(other.Get(i))
+		}
+		for i < other.len {
+			leaf := other.tail
+			if p := other.offset + i; p < other.tailOffset() {
+				leaf = other.leafNodeFor(p)
+			}
+			b.v = b.v.appendLeaf(b.gen, leaf)
+			i += len(leaf.values)
+		}
+	}
+	for ; i < other.len; i++ {
+		b.Append(other.Get(i))
+	}
+	return b.Vector()
+}
+
+// All returns an iterator over the index-item pairs in order.
+func (v ImmutableVector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		tailOffset := v.tailOffset()
+		for start := v.offset &^ vectorMask; start < tailOffset; start += vectorWidth {
+			values := v.leafFor(start)
+			for j := max(v.offset-start, 0); j < len(values); j++ {
+				if !yield(start+j-v.offset, values[j]) {
+					return
+				}
+			}
+		}
+		values := v.tail.leafValues()
+		for j := max(v.offset-tailOffset, 0); j < len(values); j++ {
+			if !yield(tailOffset+j-v.offset, values[j]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the items in order.
+func (v ImmutableVector[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range v.All() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice returns a new slice that contains all the items.
+func (v ImmutableVector[T]) ToSlice() []T {
+	result := make([]T, 0, v.len)
+	for _, item := range v.All() {
+		result = append(result, item)
+	}
+	return result
+}
+
+// Builder returns a builder that starts with the items of this vector.
+// The vector itself is not affected by changes made using the builder.
+func (v ImmutableVector[T]) Builder() *ImmutableVectorBuilder[T] {
+	return &ImmutableVectorBuilder[T]{
+		v:   v,
+		gen: hamtGeneration.Add(1),
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// ImmutableVectorBuilder is used to efficiently make many changes to an [ImmutableVector].
+// Unlike the vector itself, the builder modifies the nodes it created in place instead of copying them.
+// A builder is not safe for concurrent use.
+type ImmutableVectorBuilder[T any] struct {
+	v   ImmutableVector[T]
+	gen uint64
+}
+
+// NewImmutableVectorBuilder creates a new builder for an empty vector.
+func NewImmutableVectorBuilder[T any]() *ImmutableVectorBuilder[T] {
+	return ImmutableVector[T]{}.Builder()
+}
+
+// Len returns the number of items.
+func (b *ImmutableVectorBuilder[T]) Len() int {
+	return b.v.len
+}
+
+// Get returns the item at index i.
+// Panics if i is out of range.
+func (b *ImmutableVectorBuilder[T]) Get(i int) T {
+	return b.v.Get(i)
+}
+
+// Append adds the item to the end.
+func (b *ImmutableVectorBuilder[T]) Append(item T) {
+	b.v = b.v.append(b.gen, item)
+}
+
+// Set replaces the item at index i.
+// Panics if i is out of range.
+func (b *ImmutableVectorBuilder[T]) Set(i int, item T) {
+	b.v = b.v.set(b.gen, i, item)
+}
+
+// Vector returns the immutable vector with all the changes made so far.
+// The builder can continue to be used afterwards without affecting the vector returned.
+func (b *ImmutableVectorBuilder[T]) Vector() ImmutableVector[T] {
+	// The nodes are now shared with the vector and may no longer be modified in place
+	b.gen = hamtGeneration.Add(1)
+	return b.v
+}
+
+//-----------------------------------------------------------------------------
+
+// The methods below work with positions in the trie, the item at index i is at position offset + i.
+
+// end returns the position after the last item.
+func (v ImmutableVector[T]) end() int {
+	return v.offset + v.len
+}
+
+// tailOffset returns the position of the first value stored in the tail.
+func (v ImmutableVector[T]) tailOffset() int {
+	return v.end() - len(v.tail.leafValues())
+}
+
+// get returns the item at position p.
+func (v ImmutableVector[T]) get(p int) T {
+	if tailOffset := v.tailOffset(); p >= tailOffset {
+		return v.tail.values[p-tailOffset]
+	}
+	return v.leafFor(p)[p&vectorMask]
+}
+
+// leafFor returns the values of the leaf that stores position p.
+func (v ImmutableVector[T]) leafFor(p int) []T {
+	return v.leafNodeFor(p).values
+}
+
+// leafNodeFor returns the leaf in the trie that stores position p.
+func (v ImmutableVector[T]) leafNodeFor(p int) *vectorNode[T] {
+	n := v.root
+	for shift := v.shift; shift > 0; shift -= vectorBits {
+		n = n.children[(p>>shift)&vectorMask]
+	}
+	return n
+}
+
+// append returns the vector with the item added to the end.
+func (v ImmutableVector[T]) append(gen uint64, item T) ImmutableVector[T] {
+	switch {
+	case v.tail == nil:
+		v.tail = &vectorNode[T]{gen: gen, values: make([]T, 0, vectorWidth)}
+	case len(v.tail.values) == vectorWidth:
+		v.pushTail(gen)
+		v.tail = &vectorNode[T]{gen: gen, values: make([]T, 0, vectorWidth)}
+	default:
+		v.tail = v.tail.editable(gen)
+	}
+	v.tail.values = append(v.tail.values, item)
+	v.len++
+	return v
+}
+
+// pushTail moves the full tail into the trie.
+func (v *ImmutableVector[T]) pushTail(gen uint64) {
+	leaf := v.tail
+	count := v.tailOffset()
+	switch {
+	case v.root == nil:
+		v.root = &vectorNode[T]{gen: gen, children: []*vectorNode[T]{leaf}}
+		v.shift = vectorBits
+	case count == vectorWidth<<v.shift:
+		// The trie is full and needs another level
+		v.root = &vectorNode[T]{gen: gen, children: []*vectorNode[T]{v.root, newVectorPath(gen, v.shift, leaf)}}
+		v.shift += vectorBits
+	default:
+		v.root = v.root.pushLeaf(gen, v.shift, count, leaf)
+	}
+	v.tail = nil
+}
+
+// appendLeaf returns the vector with the values of the leaf added to the end without copying them.
+// The end of the vector must be at a leaf boundary.
+func (v ImmutableVector[T]) appendLeaf(gen uint64, leaf *vectorNode[T]) ImmutableVector[T] {
+	if v.tail != nil {
+		v.pushTail(gen)
+	}
+	v.tail = leaf
+	v.len += len(leaf.values)
+	return v
+}
+
+// set returns the vector with the item at index i replaced.
+func (v ImmutableVector[T]) set(gen uint64, i int, item T) ImmutableVector[T] {
+	checkIndex(i, v.len)
+	p := v.offset + i
+	if tailOffset := v.tailOffset(); p >= tailOffset {
+		v.tail = v.tail.editable(gen)
+		v.tail.values[p-tailOffset] = item
+		return v
+	}
+	v.root = v.root.set(gen, v.shift, p, item)
+	return v
+}
+
+// truncate returns the vector with only the first n items where 0 < n < v.len.
+func (v ImmutableVector[T]) truncate(n int) ImmutableVector[T] {
+	end := v.offset + n
+	tailOffset := ((end - 1) >> vectorBits) << vectorBits
+	if tailOffset >= v.tailOffset() {
+		v.tail = &vectorNode[T]{values: v.tail.values[: end-tailOffset : end-tailOffset]}
+		v.len = n
+		return v
+	}
+
+	values := v.leafFor(tailOffset)
+	result := ImmutableVector[T]{
+		tail:   &vectorNode[T]{values: values[: end-tailOffset : end-tailOffset]},
+		offset: v.offset,
+		len:    n,
+	}
+	if tailOffset <= v.offset {
+		// All the items are in the new tail
+		result.offset -= tailOffset
+		return result
+	}
+
+	result.root = v.root.truncate(v.shift, tailOffset)
+	result.shift = v.shift
+	return result.trimFront()
+}
+
+// trimFront returns the vector without the parts of the trie that only contain positions before the offset.
+// This keeps the trie as shallow as possible and allows the unused items to be garbage collected.
+func (v ImmutableVector[T]) trimFront() ImmutableVector[T] {
+	if tailOffset := v.tailOffset(); v.offset >= tailOffset {
+		// All the items are in the tail
+		v.offset -= tailOffset
+		v.root = nil
+		v.shift = 0
+		return v
+	}
+
+	for {
+		// Moving all positions back by a multiple of the size of a child keeps the leaves aligned
+		if k := v.offset >> v.shift; k > 0 {
+			v.root = &vectorNode[T]{children: slices.Clone(v.root.children[k:])}
+			v.offset -= k << v.shift
+		}
+		if v.shift == vectorBits || len(v.root.children) > 1 {
+			break
+		}
+		v.root = v.root.children[0]
+		v.shift -= vectorBits
+	}
+	v.root = v.root.dropBefore(v.shift, v.offset)
+	return v
+}
+
+// leafValues returns the values of the leaf n which may be nil.
+func (n *vectorNode[T]) leafValues() []T {
+	if n == nil {
+		return nil
+	}
+	return n.values
+}
+
+// editable returns n if it may be modified in place by the generation, otherwise a copy of n.
+func (n *vectorNode[T]) editable(gen uint64) *vectorNode[T] {
+	if gen != 0 && n.gen == gen {
+		return n
+	}
+	m := &vectorNode[T]{gen: gen, children: slices.Clone(n.children)}
+	if n.values != nil {
+		// Leaves never grow beyond 32 values so reserve the space upfront
+		m.values = append(make([]T, 0, vectorWidth), n.values...)
+	}
+	return m
+}
+
+// pushLeaf returns the node with the leaf added for the items starting at index count.
+func (n *vectorNode[T]) pushLeaf(gen uint64, shift uint, count int, leaf *vectorNode[T]) *vectorNode[T] {
+	m := n.editable(gen)
+	i := (count >> shift) & vectorMask
+	switch {
+	case shift == vectorBits:
+		m.children = append(m.children, leaf)
+	case i < len(m.children):
+		m.children[i] = m.children[i].pushLeaf(gen, shift-vectorBits, count, leaf)
+	default:
+		m.children = append(m.children, newVectorPath(gen, shift-vectorBits, leaf))
+	}
+	return m
+}
+
+// newVectorPath returns a chain of nodes from the level at shift down to the leaf.
+func newVectorPath[T any](gen uint64, shift uint, leaf *vectorNode[T]) *vectorNode[T] {
+	if shift == 0 {
+		return leaf
+	}
+	return &vectorNode[T]{gen: gen, children: []*vectorNode[T]{newVectorPath(gen, shift-vectorBits, leaf)}}
+}
+
+func (n *vectorNode[T]) set(gen uint64, shift uint, i int, item T) *vectorNode[T] {
+	m := n.editable(gen)
+	if shift == 0 {
+		m.values[i&vectorMask] = item
+		return m
+	}
+	j := (i >> shift) & vectorMask
+	m.children[j] = m.children[j].set(gen, shift-vectorBits, i, item)
+	return m
+}
+
+// dropBefore returns the node with the children that only contain positions before p removed.
+func (n *vectorNode[T]) dropBefore(shift uint, p int) *vectorNode[T] {
+	if shift == 0 {
+		return n
+	}
+	j := (p >> shift) & vectorMask
+	child := n.children[j].dropBefore(shift-vectorBits, p)
+	if j == 0 && child == n.children[0] {
+		return n
+	}
+	m := &vectorNode[T]{children: slices.Clone(n.children)}
+	clear(m.children[:j])
+	m.children[j] = child
+	return m
+}
+
+// truncate returns a new node that only contains the first count items where count is a multiple of 32.
+func (n *vectorNode[T]) truncate(shift uint, count int) *vectorNode[T] {
+	last := ((count - 1) >> shift) & vectorMask
+	m := &vectorNode[T]{children: slices.Clone(n.children[:last+1])}
+	if shift > vectorBits && count&(1<<shift-1) != 0 {
+		m.children[last] = m.children[last].truncate(shift-vectorBits, count&(1<<shift-1))
+	}
+	return m
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableVectorAppendGetSet(t *testing.T) {
+	var empty collection.ImmutableVector[int]
+	assert.Equal(t, 0, empty.Len())
+	assert.Empty(t, empty.ToSlice())
+	require.Panics(t, func() { empty.Get(0) })
+
+	v1 := empty.Append(1, 2, 3)
+	v2 := v1.Append(4)
+	v3 := v2.Set(0, 10)
+
+	assert.Equal(t, []int{1, 2, 3}, v1.ToSlice())
+	assert.Equal(t, []int{1, 2, 3, 4}, v2.ToSlice())
+	assert.Equal(t, []int{10, 2, 3, 4}, v3.ToSlice())
+	assert.Equal(t, 4, v3.Get(3))
+	require.Panics(t, func() { v3.Get(4) })
+	require.Panics(t, func() { v3.Set(-1, 0) })
+
+	// Appending to the same version twice must not affect each other
+	a := v1.Append(100)
+	b := v1.Append(200)
+	assert.Equal(t, []int{1, 2, 3, 100}, a.ToSlice())
+	assert.Equal(t, []int{1, 2, 3, 200}, b.ToSlice())
+}
+
+func TestImmutableVectorLarge(t *testing.T) {
+	const n = 40000
+	v := collection.NewImmutableVector[int]()
+	versions := make([]collection.ImmutableVector[int], 0)
+	for i := 0; i < n; i++ {
+		v = v.Append(i)
+		if i%997 == 0 {
+			versions = append(versions, v)
+		}
+	}
+	require.Equal(t, n, v.Len())
+	for i := 0; i < n; i++ {
+		require.Equal(t, i, v.Get(i))
+	}
+
+	updated := v
+	for i := 0; i < n; i += 7 {
+		updated = updated.Set(i, -i)
+	}
+	for i := 0; i < n; i++ {
+		if i%7 == 0 {
+			require.Equal(t, -i, updated.Get(i))
+		} else {
+			require.Equal(t, i, updated.Get(i))
+		}
+		require.Equal(t, i, v.Get(i))
+	}
+
+	for i, version := range versions {
+		require.Equal(t, i*997+1, version.Len())
+		for j, item := range version.All() {
+			require.Equal(t, j, item)
+		}
+	}
+}
+
+func TestImmutableVectorSlice(t *testing.T) {
+	items := make([]int, 3000)
+	for i := range items {
+		items[i] = i
+	}
+	v := collection.NewImmutableVector(items...)
+
+	for _, r := range [][2]int{{0, 3000}, {0, 0}, {0, 1}, {0, 32}, {0, 33}, {0, 1024}, {0, 1025},
+		{0, 1056}, {0, 2999}, {5, 10}, {100, 2500}, {3000, 3000}, {1, 3000}, {31, 33}, {32, 64},
+		{1023, 1025}, {1024, 3000}, {2990, 3000}, {2999, 3000}, {40, 41}} {
+		s := v.Slice(r[0], r[1])
+		require.Equal(t, r[1]-r[0], s.Len(), r)
+		assert.Equal(t, items[r[0]:r[1]], s.ToSlice(), r)
+
+		// The slice must remain fully usable
+		s = s.Append(-1).Set(s.Len(), -2)
+		assert.Equal(t, -2, s.Get(s.Len()-1))
+	}
+	assert.Equal(t, items, v.ToSlice())
+
+	// Slices of slices
+	s := v.Slice(10, 2000).Slice(1000, 1990).Slice(5, 900)
+	assert.Equal(t, items[1015:1910], s.ToSlice())
+	for i, item := range s.All() {
+		require.Equal(t, items[1015+i], item)
+	}
+	s = s.Set(0, -1)
+	assert.Equal(t, -1, s.Get(0))
+	assert.Equal(t, 1015, v.Get(1015))
+
+	require.Panics(t, func() { v.Slice(-1, 2) })
+	require.Panics(t, func() { v.Slice(2, 1) })
+	require.Panics(t, func() { v.Slice(0, 3001) })
+}
+
+func TestImmutableVectorConcat(t *testing.T) {
+	a := collection.NewImmutableVector(1, 2, 3)
+	b := collection.NewImmutableVectorFromSeq(slices.Values([]int{4, 5}))
+	c := a.Concat(b)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, c.ToSlice())
+	assert.Equal(t, []int{1, 2, 3}, a.ToSlice())
+	assert.Equal(t, []int{4, 5}, collection.ImmutableVector[int]{}.Concat(b).ToSlice())
+	assert.Equal(t, []int{1, 2, 3}, a.Concat(collection.ImmutableVector[int]{}).ToSlice())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(c.Values()))
+
+	items := make([]int, 3000)
+	for i := range items {
+		items[i] = i
+	}
+	v := collection.NewImmutableVector(items...)
+	for _, r := range [][4]int{{0, 64, 0, 3000}, {0, 64, 32, 3000}, {0, 100, 36, 1000}, {0, 100, 37, 1000},
+		{10, 50, 0, 100}, {1000, 2024, 1024, 3000}, {0, 1024, 0, 1024}, {0, 1, 1, 3000}, {0, 32, 0, 20}} {
+		a, b := v.Slice(r[0], r[1]), v.Slice(r[2], r[3])
+		c := a.Concat(b)
+		expected := slices.Concat(items[r[0]:r[1]], items[r[2]:r[3]])
+		require.Equal(t, expected, c.ToSlice(), r)
+
+		// Changes to the result must not affect the vectors it was made from
+		c = c.Set(c.Len()-1, -1).Set(0, -2).Append(-3)
+		assert.Equal(t, items[r[0]:r[1]], a.ToSlice(), r)
+		assert.Equal(t, items[r[2]:r[3]], b.ToSlice(), r)
+		assert.Equal(t, -3, c.Get(len(expected)))
+	}
+}
+
+func TestImmutableVectorBuilder(t *testing.T) {
+	base := collection.NewImmutableVector(1, 2, 3)
+	b := base.Builder()
+	for i := 0; i < 100; i++ {
+		b.Append(i)
+	}
+	b.Set(0, 42)
+	assert.Equal(t, 103, b.Len())
+	assert.Equal(t, 42, b.Get(0))
+
+	v1 := b.Vector()
+	b.Set(1, 43)
+	b.Set(102, 44)
+	b.Append(45)
+	v2 := b.Vector()
+
+	assert.Equal(t, []int{1, 2, 3}, base.ToSlice())
+	assert.Equal(t, 103, v1.Len())
+	assert.Equal(t, 2, v1.Get(1))
+	assert.Equal(t, 99, v1.Get(102))
+	assert.Equal(t, 104, v2.Len())
+	assert.Equal(t, 43, v2.Get(1))
+	assert.Equal(t, 44, v2.Get(102))
+	assert.Equal(t, 45, v2.Get(103))
+}
+
+func TestImmutableVectorModel(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	v := collection.NewImmutableVector[int]()
+	model := []int{}
+
+	type version struct {
+		v     collection.ImmutableVector[int]
+		model []int
+	}
+	var versions []version
+
+	for i := 0; i < 5000; i++ {
+		switch op := rnd.IntN(10); {
+		case op < 6:
+			v = v.Append(i)
+			model = append(model, i)
+		case op < 8 && len(model) > 0:
+			j := rnd.IntN(len(model))
+			v = v.Set(j, i)
+			model[j] = i
+		case op < 9 && len(model) > 0:
+			hi := rnd.IntN(len(model) + 1)
+			lo := rnd.IntN(hi + 1)
+			v = v.Slice(lo, hi)
+			model = slices.Clone(model[lo:hi])
+		case len(model) > 0:
+			// Concat part of the vector onto itself which shares the leaves when they line up
+			lo := rnd.IntN(len(model))
+			v = v.Concat(v.Slice(lo, len(model)))
+			model = append(model, model[lo:]...)
+		}
+		require.Equal(t, len(model), v.Len())
+
+		if i%250 == 0 {
+			versions = append(versions, version{v: v, model: slices.Clone(model)})
+		}
+	}
+
+	assert.Equal(t, model, v.ToSlice())
+	for _, ver := range versions {
+		assert.Equal(t, ver.model, ver.v.ToSlice())
+	}
+}
+
+func BenchmarkImmutableVector(b *testing.B) {
+	const items = 10000
+
+	b.Run("Append", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			v := collection.NewImmutableVector[int]()
+			for j := 0; j < items; j++ {
+				v = v.Append(j)
+			}
+		}
+	})
+
+	b.Run("Builder", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			builder := collection.NewImmutableVectorBuilder[int]()
+			for j := 0; j < items; j++ {
+				builder.Append(j)
+			}
+			_ = builder.Vector()
+		}
+	})
+
+	b.Run("Slice", func(b *testing.B) {
+		v := collection.NewImmutableVector(make([]int, items)...)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = v.Slice(i%(items/2), items-i%(items/2))
+		}
+	})
+
+	b.Run("Get", func(b *testing.B) {
+		v := collection.NewImmutableVector(make([]int, items)...)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = v.Get(i % items)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import "iter"
+
+// Collection is a group of items that can be counted, searched and iterated.
+// It is implemented by [Set], [ImmutableSet], [RangeSet], [DisjointSet] and [ReadOnlySet].
+// [Graph] and [WeightedGraph] implement it for their nodes.
+//
+// Some containers deliberately do not implement any of the interfaces:
+//   - [ImmutableVector] is an ordered sequence that can hold items that are not comparable, so it
+//     can't provide Contains, and All iterates over index-item pairs. Use [ImmutableVector.Values]
+//     to iterate over the items instead.
+//   - [IntervalTree] identifies each value by a pair of bounds instead of a single key, so it does
+//     not fit [MapLike].
+//   - [PriorityQueue] only gives access to the item with the highest priority, searching or
+//     iterating would require removing all the items.
+type Collection[T any] interface {
+	// Len returns the number of items in the collection.
+	Len() int
+	// Contains returns true if the item is in the collection.
+	Contains(item T) bool
+	// All returns an iterator over all the items in the collection.
+	All() iter.Seq[T]
+}
+
+// MutableCollection is a [Collection] that items can be inserted into and removed from.
+// It is implemented by [Set] and [RangeSet].
+// Custom implementations can be checked with the suites in the collectiontest package.
+type MutableCollection[T any] interface {
+	Collection[T]
+	// Insert adds the item to the collection.
+	// Returns true if the collection was changed.
+	Insert(item T) bool
+	// Remove removes the item from the collection.
+	// Returns true if the item was in the collection before removing.
+	Remove(item T) bool
+}
+
+// SetLike is a [MutableCollection] that stores each item at most once and can return all the items as a slice.
+// Insert must return false when the item is already in the set.
+// It is implemented by [Set].
+type SetLike[T any] interface {
+	MutableCollection[T]
+	// Items returns a new slice that contains all the items in the set.
+	Items() []T
+}
+
+// MapLike is a group of key-value pairs that can be counted, searched and iterated.
+// It is implemented by [ImmutableMap], [ReadOnlyMap] and all of the types that implement [MutableMapLike].
+type MapLike[K any, V any] interface {
+	// Len returns the number of key-value pairs.
+	Len() int
+	// Contains returns true if the key exists.
+	Contains(key K) bool
+	// Get returns the value for the key and true if the key exists.
+	Get(key K) (V, bool)
+	// All returns an iterator over all the key-value pairs.
+	All() iter.Seq2[K, V]
+}
+
+// MutableMapLike is a [MapLike] that key-value pairs can be stored in and removed from.
+// It is implemented by [Trie], [SliceTrie], [RadixTree] and [PrefixTree].
+// Custom implementations can be checked with collectiontest.RunMapSuite.
+type MutableMapLike[K any, V any] interface {
+	MapLike[K, V]
+	// Put stores the value for the key.
+	// Returns true if the key is new and false if an existing value was replaced.
+	Put(key K, value V) bool
+	// Delete removes the key.
+	// Returns true if the key existed before being removed.
+	Delete(key K) bool
+}
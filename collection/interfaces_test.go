@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/bits"
+	"net/netip"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/andrejacobs/go-collection/collection/collectiontest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Compile time checks that the containers implement the interfaces.
+var (
+	_ collection.SetLike[int]           = collection.NewSet[int]()
+	_ collection.Collection[int]        = collection.NewSet[int]().AsReadOnly()
+	_ collection.Collection[int]        = collection.NewImmutableSet[int]()
+	_ collection.MutableCollection[int] = collection.NewRangeSet[int]()
+	_ collection.Collection[int]        = collection.NewDisjointSet[int]()
+	_ collection.Collection[string]     = collection.NewGraph[string]()
+	_ collection.Collection[string]     = collection.NewWeightedGraph[string, int]()
+
+	_ collection.MapLike[string, int]               = collection.NewImmutableMap[string, int]()
+	_ collection.MapLike[string, int]               = collection.MapAsReadOnly(map[string]int{})
+	_ collection.MutableMapLike[string, int]        = collection.NewTrie[int]()
+	_ collection.MutableMapLike[[]rune, int]        = collection.NewSliceTrie[rune, int]()
+	_ collection.MutableMapLike[string, int]        = collection.NewRadixTree[int]()
+	_ collection.MutableMapLike[netip.Prefix, bool] = collection.NewPrefixTree[bool]()
+)
+
+func TestMutableCollectionConformance(t *testing.T) {
+	item := func(i int) int { return i }
+	t.Run("Set", func(t *testing.T) {
+		collectiontest.RunSetSuite(t, collection.NewSet[int], item)
+	})
+	t.Run("RangeSet", func(t *testing.T) {
+		collectiontest.RunCollectionSuite(t, func() collection.MutableCollection[int] {
+			return collection.NewRangeSet[int]()
+		}, item)
+	})
+}
+
+func TestMutableMapLikeConformance(t *testing.T) {
+	// Every binary string exactly once: "", "0", "1", "00", ... so that many keys are prefixes of each other
+	stringKey := func(i int) string {
+		return strconv.FormatUint(uint64(i)+1, 2)[1:]
+	}
+	value := func(i int) int { return i }
+
+	t.Run("Trie", func(t *testing.T) {
+		collectiontest.RunMapSuite(t, func() collection.MutableMapLike[string, int] {
+			return collection.NewTrie[int]()
+		}, stringKey, value)
+	})
+	t.Run("RadixTree", func(t *testing.T) {
+		collectiontest.RunMapSuite(t, func() collection.MutableMapLike[string, int] {
+			return collection.NewRadixTree[int]()
+		}, stringKey, value)
+	})
+	t.Run("RadixTreeSnapshot", func(t *testing.T) {
+		// A snapshot must behave exactly like a tree that was created from scratch
+		collectiontest.RunMapSuite(t, func() collection.MutableMapLike[string, int] {
+			tr := collection.NewRadixTree[int]()
+			tr.Put("1", 1)
+			s := tr.Snapshot()
+			s.Delete("1")
+			return s
+		}, stringKey, value)
+	})
+	t.Run("PrefixTree", func(t *testing.T) {
+		// The same bits as stringKey which gives nested prefixes, starting with 0.0.0.0/0
+		collectiontest.RunMapSuite(t, func() collection.MutableMapLike[netip.Prefix, int] {
+			return collection.NewPrefixTree[int]()
+		}, func(i int) netip.Prefix {
+			n := uint32(i) + 1
+			length := 31 - bits.LeadingZeros32(n)
+			addr := (n &^ (1 << length)) << (32 - length)
+			return netip.PrefixFrom(netip.AddrFrom4([4]byte{byte(addr >> 24), byte(addr >> 16), byte(addr >> 8), byte(addr)}), length)
+		}, value)
+	})
+}
+
+func TestCollectionGenericUse(t *testing.T) {
+	count := func(c collection.Collection[int], items ...int) int {
+		n := 0
+		for _, item := range items {
+			if c.Contains(item) {
+				n++
+			}
+		}
+		return n
+	}
+
+	s := collection.NewSetFrom([]int{1, 2, 3})
+	assert.Equal(t, 2, count(s, 1, 3, 5))
+	assert.Equal(t, 2, count(s.AsReadOnly(), 1, 3, 5))
+	assert.Equal(t, 1, count(collection.NewImmutableSet(5), 1, 3, 5))
+	assert.Equal(t, 3, count(collection.NewDisjointSet(1, 3, 5), 1, 3, 5))
+
+	r := collection.NewRangeSet[int]()
+	r.InsertRange(1, 4)
+	assert.Equal(t, 2, count(r, 1, 3, 5))
+
+	g := collection.NewGraph[int]()
+	g.AddEdge(3, 1)
+	g.AddNode(2)
+	assert.Equal(t, 2, count(g, 1, 3, 5))
+	assert.Equal(t, []int{3, 1, 2}, slices.Collect(g.All()))
+
+	wg := collection.NewWeightedGraph[int, float64]()
+	wg.AddEdge(5, 1, 0.5)
+	assert.Equal(t, 2, count(wg, 1, 3, 5))
+	assert.Equal(t, []int{5, 1}, slices.Collect(wg.All()))
+}
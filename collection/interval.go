@@ -0,0 +1,286 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"cmp"
+	"iter"
+)
+
+// See https://en.wikipedia.org/wiki/Interval_tree for the data structure.
+
+// Interval is a closed range [Lo, Hi] with an associated value.
+type Interval[K cmp.Ordered, V any] struct {
+	Lo    K
+	Hi    K
+	Value V
+}
+
+// Overlaps returns true if the interval overlaps the closed range [lo, hi].
+func (i Interval[K, V]) Overlaps(lo K, hi K) bool {
+	return i.Lo <= hi && lo <= i.Hi
+}
+
+// Contains returns true if the point is within the interval.
+func (i Interval[K, V]) Contains(point K) bool {
+	return i.Lo <= point && point <= i.Hi
+}
+
+// IntervalTree stores closed intervals [lo, hi] and can efficiently find all the intervals that
+// overlap a range or contain a point.
+// Each distinct [lo, hi] interval is stored only once and is associated with a value of type V.
+// The tree is kept balanced (AVL) and augmented with the maximum Hi of each subtree, so that
+// queries run in O(log n + m) time where m is the number of intervals returned.
+// The zero value is an empty tree ready to use.
+type IntervalTree[K cmp.Ordered, V any] struct {
+	root *intervalNode[K, V]
+	len  int
+}
+
+type intervalNode[K cmp.Ordered, V any] struct {
+	interval    Interval[K, V]
+	maxHi       K
+	height      int
+	left, right *intervalNode[K, V]
+}
+
+// NewIntervalTree creates a new empty interval tree.
+func NewIntervalTree[K cmp.Ordered, V any]() *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{}
+}
+
+// Len returns the number of intervals stored in the tree.
+func (t *IntervalTree[K, V]) Len() int {
+	return t.len
+}
+
+// Insert stores the value for the interval [lo, hi].
+// Returns true if the interval is new and false if the value of an existing interval was replaced.
+// The interval is ignored and false is returned if lo > hi.
+func (t *IntervalTree[K, V]) Insert(lo K, hi K, value V) bool {
+	if lo > hi {
+		return false
+	}
+
+	added := false
+	t.root = t.root.insert(Interval[K, V]{Lo: lo, Hi: hi, Value: value}, &added)
+	if added {
+		t.len++
+	}
+	return added
+}
+
+// Get returns the value for the exact interval [lo, hi] and true if the interval exists.
+func (t *IntervalTree[K, V]) Get(lo K, hi K) (V, bool) {
+	n := t.root
+	for n != nil {
+		switch c := compareInterval(lo, hi, n.interval); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.interval.Value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes the exact interval [lo, hi] from the tree.
+// Returns true if the interval existed before being removed.
+func (t *IntervalTree[K, V]) Delete(lo K, hi K) bool {
+	deleted := false
+	t.root = t.root.delete(lo, hi, &deleted)
+	if deleted {
+		t.len--
+	}
+	return deleted
+}
+
+// Overlapping returns an iterator over all the intervals that overlap the closed range [lo, hi]
+// ordered by the start of the interval.
+func (t *IntervalTree[K, V]) Overlapping(lo K, hi K) iter.Seq[Interval[K, V]] {
+	return func(yield func(Interval[K, V]) bool) {
+		if lo > hi {
+			return
+		}
+		t.root.overlapping(lo, hi, yield)
+	}
+}
+
+// Containing returns an iterator over all the intervals that contain the point
+// ordered by the start of the interval.
+func (t *IntervalTree[K, V]) Containing(point K) iter.Seq[Interval[K, V]] {
+	return t.Overlapping(point, point)
+}
+
+// AnyOverlapping returns true if at least one interval overlaps the closed range [lo, hi].
+func (t *IntervalTree[K, V]) AnyOverlapping(lo K, hi K) bool {
+	for range t.Overlapping(lo, hi) {
+		return true
+	}
+	return false
+}
+
+// All returns an iterator over all the intervals ordered by the start of the interval.
+// Intervals with the same start are ordered by their end.
+func (t *IntervalTree[K, V]) All() iter.Seq[Interval[K, V]] {
+	return func(yield func(Interval[K, V]) bool) {
+		t.root.walk(yield)
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// compareInterval orders intervals by Lo and then by Hi.
+func compareInterval[K cmp.Ordered, V any](lo K, hi K, i Interval[K, V]) int {
+	if c := cmp.Compare(lo, i.Lo); c != 0 {
+		return c
+	}
+	return cmp.Compare(hi, i.Hi)
+}
+
+func (n *intervalNode[K, V]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update recalculates the height and maximum Hi after the children changed.
+func (n *intervalNode[K, V]) update() {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+	n.maxHi = n.interval.Hi
+	if n.left != nil && n.left.maxHi > n.maxHi {
+		n.maxHi = n.left.maxHi
+	}
+	if n.right != nil && n.right.maxHi > n.maxHi {
+		n.maxHi = n.right.maxHi
+	}
+}
+
+func (n *intervalNode[K, V]) rotateLeft() *intervalNode[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *intervalNode[K, V]) rotateRight() *intervalNode[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// balance restores the AVL invariant and returns the new root of the subtree.
+func (n *intervalNode[K, V]) balance() *intervalNode[K, V] {
+	n.update()
+	bf := n.left.getHeight() - n.right.getHeight()
+	switch {
+	case bf > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case bf < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+func (n *intervalNode[K, V]) insert(i Interval[K, V], added *bool) *intervalNode[K, V] {
+	if n == nil {
+		*added = true
+		return &intervalNode[K, V]{interval: i, maxHi: i.Hi, height: 1}
+	}
+
+	switch c := compareInterval(i.Lo, i.Hi, n.interval); {
+	case c < 0:
+		n.left = n.left.insert(i, added)
+	case c > 0:
+		n.right = n.right.insert(i, added)
+	default:
+		n.interval.Value = i.Value
+		return n
+	}
+	return n.balance()
+}
+
+func (n *intervalNode[K, V]) delete(lo K, hi K, deleted *bool) *intervalNode[K, V] {
+	if n == nil {
+		return nil
+	}
+
+	switch c := compareInterval(lo, hi, n.interval); {
+	case c < 0:
+		n.left = n.left.delete(lo, hi, deleted)
+	case c > 0:
+		n.right = n.right.delete(lo, hi, deleted)
+	default:
+		*deleted = true
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		// Replace with the successor
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.interval = successor.interval
+		n.right = n.right.delete(successor.interval.Lo, successor.interval.Hi, new(bool))
+	}
+	return n.balance()
+}
+
+func (n *intervalNode[K, V]) overlapping(lo K, hi K, yield func(Interval[K, V]) bool) bool {
+	if n == nil || n.maxHi < lo {
+		return true
+	}
+	if !n.left.overlapping(lo, hi, yield) {
+		return false
+	}
+	if n.interval.Lo > hi {
+		// Everything to the right starts even later
+		return true
+	}
+	if n.interval.Hi >= lo && !yield(n.interval) {
+		return false
+	}
+	return n.right.overlapping(lo, hi, yield)
+}
+
+func (n *intervalNode[K, V]) walk(yield func(Interval[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.walk(yield) && yield(n.interval) && n.right.walk(yield)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalTree(t *testing.T) {
+	tr := collection.NewIntervalTree[int, string]()
+	assert.True(t, tr.Insert(15, 20, "a"))
+	assert.True(t, tr.Insert(10, 30, "b"))
+	assert.True(t, tr.Insert(17, 19, "c"))
+	assert.True(t, tr.Insert(5, 20, "d"))
+	assert.True(t, tr.Insert(12, 15, "e"))
+	assert.True(t, tr.Insert(30, 40, "f"))
+	assert.False(t, tr.Insert(30, 40, "g"))
+	assert.False(t, tr.Insert(50, 40, "invalid"))
+	assert.Equal(t, 6, tr.Len())
+
+	v, ok := tr.Get(30, 40)
+	assert.True(t, ok)
+	assert.Equal(t, "g", v)
+	_, ok = tr.Get(30, 41)
+	assert.False(t, ok)
+
+	values := func(seq iter.Seq[collection.Interval[int, string]]) []string {
+		var result []string
+		for i := range seq {
+			result = append(result, i.Value)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"d", "b", "e", "a"}, values(tr.Overlapping(14, 16)))
+	assert.Equal(t, []string{"b", "g"}, values(tr.Containing(30)))
+	assert.Equal(t, []string{"d", "b", "e", "a", "c", "g"}, values(tr.All()))
+	assert.Empty(t, values(tr.Overlapping(41, 100)))
+	assert.Empty(t, values(tr.Overlapping(20, 10)))
+	assert.True(t, tr.AnyOverlapping(0, 5))
+	assert.False(t, tr.AnyOverlapping(0, 4))
+
+	assert.True(t, tr.Delete(10, 30))
+	assert.False(t, tr.Delete(10, 30))
+	assert.Equal(t, []string{"g"}, values(tr.Containing(30)))
+	assert.Equal(t, 5, tr.Len())
+}
+
+func TestIntervalTreeZeroValue(t *testing.T) {
+	var tr collection.IntervalTree[float64, int]
+	assert.True(t, tr.Insert(0.5, 1.5, 1))
+	assert.True(t, tr.AnyOverlapping(1.5, 2))
+}
+
+func TestIntervalTreeModel(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	tr := collection.NewIntervalTree[int, int]()
+	model := make(map[[2]int]int)
+
+	for i := 0; i < 3000; i++ {
+		lo := rnd.IntN(200)
+		hi := lo + rnd.IntN(20)
+		key := [2]int{lo, hi}
+		_, exists := model[key]
+		if rnd.IntN(3) == 0 {
+			assert.Equal(t, exists, tr.Delete(lo, hi))
+			delete(model, key)
+		} else {
+			assert.Equal(t, !exists, tr.Insert(lo, hi, i))
+			model[key] = i
+		}
+		require.Equal(t, len(model), tr.Len())
+
+		qlo := rnd.IntN(220)
+		qhi := qlo + rnd.IntN(10)
+		var expected []collection.Interval[int, int]
+		for k, v := range model {
+			if k[0] <= qhi && qlo <= k[1] {
+				expected = append(expected, collection.Interval[int, int]{Lo: k[0], Hi: k[1], Value: v})
+			}
+		}
+		slices.SortFunc(expected, func(a, b collection.Interval[int, int]) int {
+			return cmp.Or(cmp.Compare(a.Lo, b.Lo), cmp.Compare(a.Hi, b.Hi))
+		})
+
+		actual := slices.Collect(tr.Overlapping(qlo, qhi))
+		if len(expected) == 0 {
+			require.Empty(t, actual)
+		} else {
+			require.Equal(t, expected, actual)
+		}
+	}
+}
+
+func BenchmarkIntervalTree(b *testing.B) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	tr := collection.NewIntervalTree[int, int]()
+	for i := 0; i < 100000; i++ {
+		lo := rnd.IntN(1000000)
+		tr.Insert(lo, lo+rnd.IntN(100), i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range tr.Containing(rnd.IntN(1000000)) {
+		}
+	}
+}
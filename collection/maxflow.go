@@ -0,0 +1,279 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"fmt"
+	"slices"
+)
+
+// See https://en.wikipedia.org/wiki/Maximum_flow_problem for the algorithms used.
+
+// MaxFlow is the result of a maximum flow calculation where the weight of each edge is its capacity.
+type MaxFlow[N comparable, W Number] struct {
+	// Value is the total amount of flow from the source to the sink.
+	Value W
+
+	flows      map[Pair[N, N]]W
+	flowEdges  []WeightedEdge[N, W]
+	sourceSide Set[N]
+	cut        []WeightedEdge[N, W]
+}
+
+// Flow returns the amount of flow along the edge from -> to.
+func (m *MaxFlow[N, W]) Flow(from N, to N) W {
+	return m.flows[Pair[N, N]{First: from, Second: to}]
+}
+
+// Flows returns every edge that carries flow where the weight is the amount of flow.
+func (m *MaxFlow[N, W]) Flows() []WeightedEdge[N, W] {
+	return slices.Clone(m.flowEdges)
+}
+
+// MinCut returns a minimum cut that separates the source from the sink.
+// The first result is the set of nodes on the source side of the cut and the second result contains
+// the edges that cross the cut (with their capacities). The total capacity of the cut equals [MaxFlow.Value].
+func (m *MaxFlow[N, W]) MinCut() (Set[N], []WeightedEdge[N, W]) {
+	return m.sourceSide, m.cut
+}
+
+// EdmondsKarp calculates the maximum flow from the source to the sink in O(V * E^2) time where the
+// weight of each edge is its capacity.
+// [ErrNegativeWeight] is returned if an edge has a negative capacity.
+func (g *WeightedGraph[N, W]) EdmondsKarp(source N, sink N) (*MaxFlow[N, W], error) {
+	net, s, t, err := newFlowNetwork(g, source, sink)
+	if err != nil {
+		return nil, err
+	}
+
+	type step struct {
+		node int
+		edge int
+	}
+
+	var total W
+	for {
+		// Find the shortest augmenting path using a breadth first search
+		prev := make([]step, len(net.adj))
+		for i := range prev {
+			prev[i].node = -1
+		}
+		prev[s].node = s
+		queue := []int{s}
+		for len(queue) > 0 && prev[t].node < 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for i, e := range net.adj[u] {
+				if e.capacity > 0 && prev[e.to].node < 0 {
+					prev[e.to] = step{node: u, edge: i}
+					queue = append(queue, e.to)
+				}
+			}
+		}
+		if prev[t].node < 0 {
+			break
+		}
+
+		bottleneck := net.adj[prev[t].node][prev[t].edge].capacity
+		for v := t; v != s; v = prev[v].node {
+			bottleneck = min(bottleneck, net.adj[prev[v].node][prev[v].edge].capacity)
+		}
+		for v := t; v != s; v = prev[v].node {
+			net.push(prev[v].node, prev[v].edge, bottleneck)
+		}
+		total += bottleneck
+	}
+
+	return net.result(total), nil
+}
+
+// Dinic calculates the maximum flow from the source to the sink in O(V^2 * E) time where the weight
+// of each edge is its capacity. It is usually faster than [WeightedGraph.EdmondsKarp] on large graphs.
+// [ErrNegativeWeight] is returned if an edge has a negative capacity.
+func (g *WeightedGraph[N, W]) Dinic(source N, sink N) (*MaxFlow[N, W], error) {
+	net, s, t, err := newFlowNetwork(g, source, sink)
+	if err != nil {
+		return nil, err
+	}
+
+	// Upper bound of the flow that can leave the source
+	var limit W
+	for _, e := range net.adj[s] {
+		limit += e.capacity
+	}
+
+	level := make([]int, len(net.adj))
+	next := make([]int, len(net.adj))
+
+	var augment func(u int, pushed W) W
+	augment = func(u int, pushed W) W {
+		if u == t {
+			return pushed
+		}
+		for ; next[u] < len(net.adj[u]); next[u]++ {
+			e := net.adj[u][next[u]]
+			if e.capacity <= 0 || level[e.to] != level[u]+1 {
+				continue
+			}
+			if f := augment(e.to, min(pushed, e.capacity)); f > 0 {
+				net.push(u, next[u], f)
+				return f
+			}
+		}
+		return 0
+	}
+
+	var total W
+	for {
+		// Build the level graph using a breadth first search
+		for i := range level {
+			level[i] = -1
+		}
+		level[s] = 0
+		queue := []int{s}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, e := range net.adj[u] {
+				if e.capacity > 0 && level[e.to] < 0 {
+					level[e.to] = level[u] + 1
+					queue = append(queue, e.to)
+				}
+			}
+		}
+		if level[t] < 0 {
+			break
+		}
+
+		// Find a blocking flow
+		clear(next)
+		for {
+			f := augment(s, limit)
+			if f <= 0 {
+				break
+			}
+			total += f
+		}
+	}
+
+	return net.result(total), nil
+}
+
+//-----------------------------------------------------------------------------
+
+type flowEdge[W Number] struct {
+	to       int
+	reverse  int // index of the reverse edge in adj[to]
+	capacity W   // remaining capacity
+	original W   // original capacity, 0 for reverse edges
+}
+
+// flowNetwork is the residual graph used by the max flow algorithms.
+type flowNetwork[N comparable, W Number] struct {
+	nodes  []N
+	adj    [][]flowEdge[W]
+	source int
+}
+
+func newFlowNetwork[N comparable, W Number](g *WeightedGraph[N, W], source N, sink N) (*flowNetwork[N, W], int, int, error) {
+	if !g.HasNode(source) {
+		return nil, 0, 0, fmt.Errorf("source node %v is not in the graph", source)
+	}
+	if !g.HasNode(sink) {
+		return nil, 0, 0, fmt.Errorf("sink node %v is not in the graph", sink)
+	}
+	if source == sink {
+		return nil, 0, 0, fmt.Errorf("source and sink must be different nodes")
+	}
+
+	net := &flowNetwork[N, W]{
+		nodes: g.Nodes(),
+	}
+	index := make(map[N]int, len(net.nodes))
+	for i, n := range net.nodes {
+		index[n] = i
+	}
+	net.adj = make([][]flowEdge[W], len(net.nodes))
+
+	for _, e := range g.Edges() {
+		if e.Weight < 0 {
+			return nil, 0, 0, ErrNegativeWeight
+		}
+		u, v := index[e.From], index[e.To]
+		net.adj[u] = append(net.adj[u], flowEdge[W]{to: v, reverse: len(net.adj[v]), capacity: e.Weight, original: e.Weight})
+		net.adj[v] = append(net.adj[v], flowEdge[W]{to: u, reverse: len(net.adj[u]) - 1})
+	}
+
+	net.source = index[source]
+	return net, net.source, index[sink], nil
+}
+
+// push sends flow along the edge adj[u][i].
+func (net *flowNetwork[N, W]) push(u int, i int, flow W) {
+	e := &net.adj[u][i]
+	e.capacity -= flow
+	net.adj[e.to][e.reverse].capacity += flow
+}
+
+func (net *flowNetwork[N, W]) result(total W) *MaxFlow[N, W] {
+	m := &MaxFlow[N, W]{
+		Value:      total,
+		flows:      make(map[Pair[N, N]]W),
+		flowEdges:  make([]WeightedEdge[N, W], 0),
+		sourceSide: NewSet[N](),
+		cut:        make([]WeightedEdge[N, W], 0),
+	}
+
+	for u, edges := range net.adj {
+		for _, e := range edges {
+			if e.original > 0 && e.capacity < e.original {
+				flow := WeightedEdge[N, W]{From: net.nodes[u], To: net.nodes[e.to], Weight: e.original - e.capacity}
+				m.flows[Pair[N, N]{First: flow.From, Second: flow.To}] = flow.Weight
+				m.flowEdges = append(m.flowEdges, flow)
+			}
+		}
+	}
+
+	// The nodes that can still be reached from the source in the residual graph form the source side of the cut
+	reached := make([]bool, len(net.nodes))
+	reached[net.source] = true
+	queue := []int{net.source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		m.sourceSide.Insert(net.nodes[u])
+		for _, e := range net.adj[u] {
+			if e.capacity > 0 && !reached[e.to] {
+				reached[e.to] = true
+				queue = append(queue, e.to)
+			}
+		}
+	}
+
+	for u, edges := range net.adj {
+		for _, e := range edges {
+			if e.original > 0 && reached[u] && !reached[e.to] {
+				m.cut = append(m.cut, WeightedEdge[N, W]{From: net.nodes[u], To: net.nodes[e.to], Weight: e.original})
+			}
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type maxFlowFunc func(g *collection.WeightedGraph[string, int], s, t string) (*collection.MaxFlow[string, int], error)
+
+var maxFlowAlgorithms = map[string]maxFlowFunc{
+	"EdmondsKarp": (*collection.WeightedGraph[string, int]).EdmondsKarp,
+	"Dinic":       (*collection.WeightedGraph[string, int]).Dinic,
+}
+
+func TestMaxFlow(t *testing.T) {
+	g := collection.NewWeightedGraph[string, int]()
+	g.AddEdge("s", "v1", 16)
+	g.AddEdge("s", "v2", 13)
+	g.AddEdge("v1", "v3", 12)
+	g.AddEdge("v2", "v1", 4)
+	g.AddEdge("v2", "v4", 14)
+	g.AddEdge("v3", "v2", 9)
+	g.AddEdge("v3", "t", 20)
+	g.AddEdge("v4", "v3", 7)
+	g.AddEdge("v4", "t", 4)
+
+	for name, maxFlow := range maxFlowAlgorithms {
+		t.Run(name, func(t *testing.T) {
+			result, err := maxFlow(g, "s", "t")
+			require.NoError(t, err)
+			assert.Equal(t, 23, result.Value)
+			assert.Equal(t, 0, result.Flow("t", "s"))
+
+			sourceSide, cut := result.MinCut()
+			assert.True(t, sourceSide.Contains("s"))
+			assert.False(t, sourceSide.Contains("t"))
+			capacity := 0
+			for _, e := range cut {
+				capacity += e.Weight
+			}
+			assert.Equal(t, 23, capacity)
+
+			outOfSource := 0
+			for _, f := range result.Flows() {
+				if f.From == "s" {
+					outOfSource += f.Weight
+				}
+			}
+			assert.Equal(t, 23, outOfSource)
+		})
+	}
+}
+
+func TestMaxFlowErrors(t *testing.T) {
+	g := collection.NewWeightedGraph[string, int]()
+	g.AddEdge("s", "t", 5)
+
+	for name, maxFlow := range maxFlowAlgorithms {
+		t.Run(name, func(t *testing.T) {
+			_, err := maxFlow(g, "s", "s")
+			assert.Error(t, err)
+			_, err = maxFlow(g, "x", "t")
+			assert.Error(t, err)
+			_, err = maxFlow(g, "s", "x")
+			assert.Error(t, err)
+
+			negative := collection.NewWeightedGraph[string, int]()
+			negative.AddEdge("s", "t", -1)
+			_, err = maxFlow(negative, "s", "t")
+			assert.ErrorIs(t, err, collection.ErrNegativeWeight)
+
+			disconnected := collection.NewWeightedGraph[string, int]()
+			disconnected.AddNode("s")
+			disconnected.AddNode("t")
+			result, err := maxFlow(disconnected, "s", "t")
+			require.NoError(t, err)
+			assert.Equal(t, 0, result.Value)
+		})
+	}
+}
+
+func TestMaxFlowBruteForceMinCut(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	nodes := []string{"s", "a", "b", "c", "d", "t"}
+
+	for run := 0; run < 50; run++ {
+		g := collection.NewWeightedGraph[string, int]()
+		for _, n := range nodes {
+			g.AddNode(n)
+		}
+		for e := 0; e < 12; e++ {
+			a, b := nodes[rnd.IntN(len(nodes))], nodes[rnd.IntN(len(nodes))]
+			if a != b {
+				g.AddEdge(a, b, rnd.IntN(10))
+			}
+		}
+
+		// The minimum cut over every partition that keeps s and t apart equals the maximum flow
+		inner := nodes[1 : len(nodes)-1]
+		best := -1
+		for mask := 0; mask < 1<<len(inner); mask++ {
+			side := collection.NewSetFrom([]string{"s"})
+			for i, n := range inner {
+				if mask&(1<<i) != 0 {
+					side.Insert(n)
+				}
+			}
+			capacity := 0
+			for _, e := range g.Edges() {
+				if side.Contains(e.From) && !side.Contains(e.To) {
+					capacity += e.Weight
+				}
+			}
+			if best < 0 || capacity < best {
+				best = capacity
+			}
+		}
+
+		for name, maxFlow := range maxFlowAlgorithms {
+			result, err := maxFlow(g, "s", "t")
+			require.NoError(t, err)
+			assert.Equal(t, best, result.Value, name)
+
+			// Flow is conserved at every node except the source and sink
+			for _, n := range inner {
+				balance := 0
+				for _, f := range result.Flows() {
+					if f.To == n {
+						balance += f.Weight
+					}
+					if f.From == n {
+						balance -= f.Weight
+					}
+				}
+				assert.Equal(t, 0, balance, name)
+			}
+		}
+	}
+}
+
+func BenchmarkMaxFlow(b *testing.B) {
+	rnd := rand.New(rand.NewPCG(5, 6))
+	g := collection.NewWeightedGraph[int, int]()
+	for e := 0; e < 5000; e++ {
+		g.AddEdge(rnd.IntN(500), rnd.IntN(500), rnd.IntN(100))
+	}
+	g.AddNode(0)
+	g.AddNode(499)
+
+	b.Run("EdmondsKarp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = g.EdmondsKarp(0, 499)
+		}
+	})
+
+	b.Run("Dinic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = g.Dinic(0, 499)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelMap returns a new slice with fn applied to every item of s using at most workers goroutines.
+// The results are in the same order as the items.
+// If workers <= 0 then runtime.GOMAXPROCS(0) workers will be used.
+//
+// If fn returns an error then the context passed to the other calls is cancelled, no new items are
+// started (the context is checked before every item) and the first error is returned. The context
+// error is returned if ctx is cancelled before all the items were processed.
+func ParallelMap[T any, U any](ctx context.Context, s []T, workers int, fn func(ctx context.Context, item T) (U, error)) ([]U, error) {
+	result := make([]U, len(s))
+	err := newParallelPlan(len(s), workers).run(ctx, func(ctx context.Context, _ int, lo int, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return context.Cause(ctx)
+			}
+			v, err := fn(ctx, s[i])
+			if err != nil {
+				return err
+			}
+			result[i] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParallelFilter returns a new slice that only contains the items for which the predicate returns true
+// using at most workers goroutines. The items remain in the same order.
+// If workers <= 0 then runtime.GOMAXPROCS(0) workers will be used.
+// Errors and cancellation are handled the same way as [ParallelMap].
+func ParallelFilter[T any](ctx context.Context, s []T, workers int, predicate func(ctx context.Context, item T) (bool, error)) ([]T, error) {
+	keep, err := ParallelMap(ctx, s, workers, predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, 0, len(s))
+	for i, item := range s {
+		if keep[i] {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// ParallelForEach calls fn for every item of s using at most workers goroutines.
+// The items are not processed in any specific order.
+// If workers <= 0 then runtime.GOMAXPROCS(0) workers will be used.
+// Errors and cancellation are handled the same way as [ParallelMap].
+func ParallelForEach[T any](ctx context.Context, s []T, workers int, fn func(ctx context.Context, item T) error) error {
+	return newParallelPlan(len(s), workers).run(ctx, func(ctx context.Context, _ int, lo int, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return context.Cause(ctx)
+			}
+			if err := fn(ctx, s[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ParallelReduce combines all the items of s into a single value using at most workers goroutines.
+// The slice is split into consecutive chunks which are each reduced with fn starting from identity,
+// after which the results of the chunks are combined in order using combine.
+// identity must not change the result when combined with any value (e.g. 0 for a sum) and combine
+// must be associative, but neither fn nor combine have to be commutative.
+// If workers <= 0 then runtime.GOMAXPROCS(0) workers will be used.
+// Errors and cancellation are handled the same way as [ParallelMap].
+func ParallelReduce[T any, U any](ctx context.Context, s []T, workers int, identity U,
+	fn func(ctx context.Context, result U, item T) (U, error), combine func(a U, b U) U) (U, error) {
+
+	plan := newParallelPlan(len(s), workers)
+	partials := make([]U, plan.chunks)
+	err := plan.run(ctx, func(ctx context.Context, chunk int, lo int, hi int) error {
+		result := identity
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return context.Cause(ctx)
+			}
+			var err error
+			result, err = fn(ctx, result, s[i])
+			if err != nil {
+				return err
+			}
+		}
+		partials[chunk] = result
+		return nil
+	})
+	if err != nil {
+		var zero U
+		return zero, err
+	}
+
+	result := identity
+	for _, partial := range partials {
+		result = combine(result, partial)
+	}
+	return result, nil
+}
+
+//-----------------------------------------------------------------------------
+
+// parallelPlan splits the indexes [0, n) into consecutive chunks that are processed by a number of workers.
+type parallelPlan struct {
+	n         int
+	workers   int
+	chunkSize int
+	chunks    int
+}
+
+func newParallelPlan(n int, workers int) parallelPlan {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = max(min(workers, n), 1)
+
+	// Use a few chunks per worker so that slow items don't leave the other workers idle, while
+	// keeping the chunks large enough that handing them out is cheap compared to the work.
+	chunkSize := max(n/(workers*4), 1)
+	return parallelPlan{
+		n:         n,
+		workers:   workers,
+		chunkSize: chunkSize,
+		chunks:    (n + chunkSize - 1) / chunkSize,
+	}
+}
+
+// run calls fn for every chunk. Returns the first error returned by fn or the error of ctx if it
+// was cancelled before all the chunks were processed.
+func (p parallelPlan) run(ctx context.Context, fn func(ctx context.Context, chunk int, lo int, hi int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		next     atomic.Int64
+		finished atomic.Int64
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for w := 0; w < p.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				chunk := int(next.Add(1) - 1)
+				if chunk >= p.chunks {
+					return
+				}
+				lo := chunk * p.chunkSize
+				hi := min(lo+p.chunkSize, p.n)
+				if err := fn(ctx, chunk, lo, hi); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+				finished.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if int(finished.Load()) < p.chunks {
+		return context.Cause(ctx)
+	}
+	return nil
+}
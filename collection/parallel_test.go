@@ -0,0 +1,233 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func numbers(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+func TestParallelMap(t *testing.T) {
+	ctx := context.Background()
+	for _, workers := range []int{0, 1, 3, 100} {
+		for _, n := range []int{0, 1, 7, 1000} {
+			result, err := collection.ParallelMap(ctx, numbers(n), workers, func(_ context.Context, i int) (string, error) {
+				return fmt.Sprint(i * 2), nil
+			})
+			require.NoError(t, err)
+			require.Len(t, result, n)
+			for i, v := range result {
+				require.Equal(t, fmt.Sprint(i*2), v)
+			}
+		}
+	}
+}
+
+func TestParallelFilter(t *testing.T) {
+	result, err := collection.ParallelFilter(context.Background(), numbers(1000), 4, func(_ context.Context, i int) (bool, error) {
+		return i%3 == 0, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 334)
+	for i, v := range result {
+		require.Equal(t, i*3, v)
+	}
+
+	result, err = collection.ParallelFilter(context.Background(), []int{}, 4, func(_ context.Context, i int) (bool, error) {
+		return true, nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestParallelForEach(t *testing.T) {
+	var sum atomic.Int64
+	err := collection.ParallelForEach(context.Background(), numbers(1001), 8, func(_ context.Context, i int) error {
+		sum.Add(int64(i))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(500500), sum.Load())
+}
+
+func TestParallelReduce(t *testing.T) {
+	ctx := context.Background()
+	sum, err := collection.ParallelReduce(ctx, numbers(1001), 8, 0,
+		func(_ context.Context, result int, i int) (int, error) { return result + i, nil },
+		func(a, b int) int { return a + b })
+	require.NoError(t, err)
+	assert.Equal(t, 500500, sum)
+
+	// Order is preserved even though string concatenation is not commutative
+	letters := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	joined, err := collection.ParallelReduce(ctx, letters, 3, "",
+		func(_ context.Context, result string, s string) (string, error) { return result + s, nil },
+		func(a, b string) string { return a + b })
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefghij", joined)
+
+	empty, err := collection.ParallelReduce(ctx, []int{}, 3, 42,
+		func(_ context.Context, result int, i int) (int, error) { return result + i, nil },
+		func(a, b int) int { return a + b })
+	require.NoError(t, err)
+	assert.Equal(t, 42, empty)
+}
+
+func TestParallelFirstErrorCancelsRemainingWork(t *testing.T) {
+	errBoom := errors.New("boom")
+	var started atomic.Int64
+
+	const workers = 4
+	_, err := collection.ParallelMap(context.Background(), numbers(10000), workers, func(ctx context.Context, i int) (int, error) {
+		started.Add(1)
+		if i == 10 {
+			// Wait until every other worker is blocked on the first item of its own chunk
+			for started.Load() < 11+workers-1 {
+				time.Sleep(100 * time.Microsecond)
+			}
+			return 0, errBoom
+		}
+		if i > 10 {
+			// Block until cancelled but don't return the error, so stopping early relies on
+			// ParallelMap checking ctx before the next item
+			<-ctx.Done()
+		}
+		return i, nil
+	})
+	require.ErrorIs(t, err, errBoom)
+	// Items 0..10 from the first chunk and a single item from each of the other workers
+	assert.Equal(t, int64(11+workers-1), started.Load())
+
+	err = collection.ParallelForEach(context.Background(), numbers(100), 4, func(_ context.Context, i int) error {
+		if i == 50 {
+			return errBoom
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errBoom)
+
+	_, err = collection.ParallelReduce(context.Background(), numbers(100), 4, 0,
+		func(_ context.Context, result int, i int) (int, error) {
+			if i == 99 {
+				return 0, errBoom
+			}
+			return result + i, nil
+		},
+		func(a, b int) int { return a + b })
+	assert.ErrorIs(t, err, errBoom)
+}
+
+func TestParallelContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	_, err := collection.ParallelMap(ctx, numbers(100), 1, func(_ context.Context, i int) (int, error) {
+		calls++
+		return i, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, calls)
+
+	// Cancelling while the work is in progress
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	err = collection.ParallelForEach(ctx, numbers(1000), 2, func(_ context.Context, i int) error {
+		if i == 5 {
+			cancel()
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// BenchmarkParallelMap compares the parallel and serial versions for different amounts of work per item.
+// The crossover point depends on the number of cores and how expensive the work per item is: the
+// parallel version has a fixed cost of starting the workers, so very cheap work needs a lot more
+// items before it pays off. Run with e.g. -cpu 1,4,8 to find the crossover on a specific machine.
+func BenchmarkParallelMap(b *testing.B) {
+	work := func(i int, rounds int) float64 {
+		x := float64(i)
+		for r := 0; r < rounds; r++ {
+			x = math.Sqrt(x + float64(r))
+		}
+		return x
+	}
+
+	for _, rounds := range []int{1, 100} {
+		for _, n := range []int{100, 10000, 1000000} {
+			s := numbers(n)
+			b.Run(fmt.Sprintf("Serial/rounds=%d/n=%d", rounds, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					result := make([]float64, len(s))
+					for j, item := range s {
+						result[j] = work(item, rounds)
+					}
+				}
+			})
+			b.Run(fmt.Sprintf("Parallel/rounds=%d/n=%d", rounds, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_, _ = collection.ParallelMap(context.Background(), s, 0, func(_ context.Context, item int) (float64, error) {
+						return work(item, rounds), nil
+					})
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkParallelReduce(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		s := numbers(n)
+		b.Run(fmt.Sprintf("Serial/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sum := 0
+				for _, item := range s {
+					sum += item
+				}
+				_ = sum
+			}
+		})
+		b.Run(fmt.Sprintf("Parallel/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = collection.ParallelReduce(context.Background(), s, 0, 0,
+					func(_ context.Context, result int, item int) (int, error) { return result + item, nil },
+					func(a, b int) int { return a + b })
+			}
+		})
+	}
+}
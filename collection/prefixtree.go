@@ -0,0 +1,168 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"iter"
+	"net/netip"
+)
+
+// PrefixTree is a routing table that maps IP prefixes (CIDRs) to values of type V.
+// Both IPv4 and IPv6 prefixes can be stored in the same tree. It is built on top of a
+// [RadixTree] and supports the same O(1) snapshots.
+// The zero value is not usable, create a new tree using [NewPrefixTree].
+type PrefixTree[V any] struct {
+	t *RadixTree[prefixEntry[V]]
+}
+
+type prefixEntry[V any] struct {
+	prefix netip.Prefix
+	value  V
+}
+
+// NewPrefixTree creates a new empty prefix tree.
+func NewPrefixTree[V any]() *PrefixTree[V] {
+	return &PrefixTree[V]{
+		t: NewRadixTree[prefixEntry[V]](),
+	}
+}
+
+// Len returns the number of prefixes stored in the tree.
+func (t *PrefixTree[V]) Len() int {
+	return t.t.Len()
+}
+
+// Snapshot returns a copy of the tree in O(1) time. See [RadixTree.Snapshot].
+func (t *PrefixTree[V]) Snapshot() *PrefixTree[V] {
+	return &PrefixTree[V]{
+		t: t.t.Snapshot(),
+	}
+}
+
+// Put stores the value for the prefix. The host bits of the prefix are ignored,
+// e.g. 10.1.2.3/8 is stored as 10.0.0.0/8.
+// Returns true if the prefix is new and false if an existing value was replaced or the prefix is invalid.
+func (t *PrefixTree[V]) Put(prefix netip.Prefix, value V) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	prefix = prefix.Masked()
+	return t.t.Put(PrefixKey(prefix), prefixEntry[V]{prefix: prefix, value: value})
+}
+
+// Get returns the value for the exact prefix and true if the prefix exists.
+func (t *PrefixTree[V]) Get(prefix netip.Prefix) (V, bool) {
+	if !prefix.IsValid() {
+		var zero V
+		return zero, false
+	}
+	entry, ok := t.t.Get(PrefixKey(prefix))
+	return entry.value, ok
+}
+
+// Contains returns true if the exact prefix exists in the tree.
+func (t *PrefixTree[V]) Contains(prefix netip.Prefix) bool {
+	_, ok := t.Get(prefix)
+	return ok
+}
+
+// Delete removes the exact prefix from the tree.
+// Returns true if the prefix existed before being removed.
+func (t *PrefixTree[V]) Delete(prefix netip.Prefix) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	return t.t.Delete(PrefixKey(prefix))
+}
+
+// LongestPrefix returns the most specific prefix in the tree that contains the address.
+// IPv4-mapped IPv6 addresses are matched against IPv4 prefixes.
+func (t *PrefixTree[V]) LongestPrefix(addr netip.Addr) (netip.Prefix, V, bool) {
+	if !addr.IsValid() {
+		var zero V
+		return netip.Prefix{}, zero, false
+	}
+	_, entry, ok := t.t.LongestPrefix(AddrKey(addr))
+	return entry.prefix, entry.value, ok
+}
+
+// Containing returns an iterator over all the prefixes that contain the address starting with the
+// least specific prefix. IPv4-mapped IPv6 addresses are matched against IPv4 prefixes.
+func (t *PrefixTree[V]) Containing(addr netip.Addr) iter.Seq2[netip.Prefix, V] {
+	return func(yield func(netip.Prefix, V) bool) {
+		if !addr.IsValid() {
+			return
+		}
+		t.t.WalkPath(AddrKey(addr), func(_ string, entry prefixEntry[V]) bool {
+			return yield(entry.prefix, entry.value)
+		})
+	}
+}
+
+// All returns an iterator over all the prefixes. IPv4 prefixes are returned before IPv6 prefixes
+// and a prefix is always returned before the more specific prefixes it contains.
+func (t *PrefixTree[V]) All() iter.Seq2[netip.Prefix, V] {
+	return func(yield func(netip.Prefix, V) bool) {
+		for _, entry := range t.t.All() {
+			if !yield(entry.prefix, entry.value) {
+				return
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// PrefixKey returns the [RadixTree] key for the prefix which is the address family ('4' or '6')
+// followed by one '0' or '1' character for each of the masked bits of the prefix.
+// Prefixes that contain each other will have keys that are prefixes of each other.
+func PrefixKey(prefix netip.Prefix) string {
+	return addrBitsKey(prefix.Addr(), prefix.Bits())
+}
+
+// AddrKey returns the [RadixTree] key for all the bits of the address.
+// See [PrefixKey]. IPv4-mapped IPv6 addresses are converted to IPv4 first.
+func AddrKey(addr netip.Addr) string {
+	addr = addr.Unmap()
+	return addrBitsKey(addr, addr.BitLen())
+}
+
+func addrBitsKey(addr netip.Addr, bits int) string {
+	if bits < 0 {
+		return ""
+	}
+
+	key := make([]byte, 0, bits+1)
+	raw := addr.AsSlice()
+	if addr.Is4() {
+		key = append(key, '4')
+	} else {
+		key = append(key, '6')
+	}
+
+	for i := 0; i < bits; i++ {
+		if raw[i/8]&(0x80>>(i%8)) != 0 {
+			key = append(key, '1')
+		} else {
+			key = append(key, '0')
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixTree(t *testing.T) {
+	tr := collection.NewPrefixTree[string]()
+	assert.True(t, tr.Put(netip.MustParsePrefix("0.0.0.0/0"), "default"))
+	assert.True(t, tr.Put(netip.MustParsePrefix("10.0.0.0/8"), "private"))
+	assert.True(t, tr.Put(netip.MustParsePrefix("10.1.0.0/16"), "office"))
+	assert.True(t, tr.Put(netip.MustParsePrefix("2001:db8::/32"), "docs"))
+	assert.False(t, tr.Put(netip.MustParsePrefix("10.1.2.3/16"), "office-2"))
+	assert.False(t, tr.Put(netip.Prefix{}, "invalid"))
+	assert.Equal(t, 4, tr.Len())
+
+	v, ok := tr.Get(netip.MustParsePrefix("10.1.0.0/16"))
+	assert.True(t, ok)
+	assert.Equal(t, "office-2", v)
+
+	prefix, v, ok := tr.LongestPrefix(netip.MustParseAddr("10.1.2.3"))
+	assert.True(t, ok)
+	assert.Equal(t, netip.MustParsePrefix("10.1.0.0/16"), prefix)
+	assert.Equal(t, "office-2", v)
+
+	prefix, _, ok = tr.LongestPrefix(netip.MustParseAddr("10.2.0.1"))
+	assert.True(t, ok)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), prefix)
+
+	prefix, _, ok = tr.LongestPrefix(netip.MustParseAddr("::ffff:192.168.1.1"))
+	assert.True(t, ok)
+	assert.Equal(t, netip.MustParsePrefix("0.0.0.0/0"), prefix)
+
+	prefix, v, ok = tr.LongestPrefix(netip.MustParseAddr("2001:db8::1"))
+	assert.True(t, ok)
+	assert.Equal(t, netip.MustParsePrefix("2001:db8::/32"), prefix)
+	assert.Equal(t, "docs", v)
+
+	_, _, ok = tr.LongestPrefix(netip.MustParseAddr("2002::1"))
+	assert.False(t, ok)
+
+	var containing []netip.Prefix
+	for p := range tr.Containing(netip.MustParseAddr("10.1.255.255")) {
+		containing = append(containing, p)
+	}
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/0"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("10.1.0.0/16"),
+	}, containing)
+
+	snap := tr.Snapshot()
+	assert.True(t, tr.Delete(netip.MustParsePrefix("10.1.0.0/16")))
+	assert.False(t, tr.Delete(netip.MustParsePrefix("10.1.0.0/16")))
+	prefix, _, _ = tr.LongestPrefix(netip.MustParseAddr("10.1.2.3"))
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), prefix)
+	prefix, _, _ = snap.LongestPrefix(netip.MustParseAddr("10.1.2.3"))
+	assert.Equal(t, netip.MustParsePrefix("10.1.0.0/16"), prefix)
+
+	var all []netip.Prefix
+	for p := range tr.All() {
+		all = append(all, p)
+	}
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/0"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}, all)
+}
+
+func TestPrefixKey(t *testing.T) {
+	assert.Equal(t, "4", collection.PrefixKey(netip.MustParsePrefix("0.0.0.0/0")))
+	assert.Equal(t, "400001010", collection.PrefixKey(netip.MustParsePrefix("10.0.0.0/8")))
+	assert.Equal(t, "6", collection.PrefixKey(netip.MustParsePrefix("::/0")))
+	assert.Len(t, collection.AddrKey(netip.MustParseAddr("192.168.0.1")), 33)
+	assert.Len(t, collection.AddrKey(netip.MustParseAddr("::1")), 129)
+}
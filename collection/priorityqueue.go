@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import "cmp"
+
+// See https://en.wikipedia.org/wiki/Binary_heap for the data structure.
+
+// PriorityQueue is a binary heap where [PriorityQueue.Pop] always returns the item with the
+// highest priority as determined by the less function, i.e. the item for which less(item, other)
+// is true for all other items.
+// Push and Pop run in O(log n) time.
+// The zero value is not usable, create a new queue using [NewPriorityQueue].
+type PriorityQueue[T any] struct {
+	items []T
+	less  func(a T, b T) bool
+}
+
+// NewPriorityQueue creates a new empty priority queue that uses the less function to order the items.
+func NewPriorityQueue[T any](less func(a T, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		less: less,
+	}
+}
+
+// NewMinPriorityQueue creates a new empty priority queue where Pop returns the smallest item first.
+func NewMinPriorityQueue[T cmp.Ordered]() *PriorityQueue[T] {
+	return NewPriorityQueue(cmp.Less[T])
+}
+
+// NewMaxPriorityQueue creates a new empty priority queue where Pop returns the largest item first.
+func NewMaxPriorityQueue[T cmp.Ordered]() *PriorityQueue[T] {
+	return NewPriorityQueue(func(a T, b T) bool {
+		return cmp.Less(b, a)
+	})
+}
+
+// Len returns the number of items in the queue.
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// Push an item onto the queue.
+func (q *PriorityQueue[T]) Push(item T) {
+	q.items = append(q.items, item)
+	q.up(len(q.items) - 1)
+}
+
+// Pop removes and returns the item with the highest priority.
+// Returns false if the queue is empty.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	var zero T
+	n := len(q.items)
+	if n == 0 {
+		return zero, false
+	}
+
+	top := q.items[0]
+	q.items[0] = q.items[n-1]
+	q.items[n-1] = zero
+	q.items = q.items[:n-1]
+	if len(q.items) > 0 {
+		q.down(0)
+	}
+	return top, true
+}
+
+// Peek returns the item with the highest priority without removing it.
+// Returns false if the queue is empty.
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[0], true
+}
+
+// Clear removes all the items from the queue.
+func (q *PriorityQueue[T]) Clear() {
+	clear(q.items)
+	q.items = q.items[:0]
+}
+
+func (q *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i], q.items[parent]) {
+			return
+		}
+		q.items[i], q.items[parent] = q.items[parent], q.items[i]
+		i = parent
+	}
+}
+
+func (q *PriorityQueue[T]) down(i int) {
+	n := len(q.items)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && q.less(q.items[left], q.items[smallest]) {
+			smallest = left
+		}
+		if right < n && q.less(q.items[right], q.items[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		q.items[i], q.items[smallest] = q.items[smallest], q.items[i]
+		i = smallest
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := collection.NewMinPriorityQueue[int]()
+	_, ok := q.Pop()
+	assert.False(t, ok)
+	_, ok = q.Peek()
+	assert.False(t, ok)
+
+	for _, v := range []int{5, 3, 9, 1, 7, 3} {
+		q.Push(v)
+	}
+	assert.Equal(t, 6, q.Len())
+
+	top, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, top)
+
+	var popped []int
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		popped = append(popped, v)
+	}
+	assert.Equal(t, []int{1, 3, 3, 5, 7, 9}, popped)
+}
+
+func TestPriorityQueueMaxAndCustom(t *testing.T) {
+	q := collection.NewMaxPriorityQueue[string]()
+	q.Push("b")
+	q.Push("c")
+	q.Push("a")
+	v, _ := q.Pop()
+	assert.Equal(t, "c", v)
+
+	q.Clear()
+	assert.Equal(t, 0, q.Len())
+
+	type task struct {
+		name     string
+		priority int
+	}
+	tasks := collection.NewPriorityQueue(func(a, b task) bool { return a.priority > b.priority })
+	tasks.Push(task{"low", 1})
+	tasks.Push(task{"high", 10})
+	tasks.Push(task{"medium", 5})
+	next, _ := tasks.Pop()
+	assert.Equal(t, "high", next.name)
+}
+
+func TestPriorityQueueRandom(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	q := collection.NewMinPriorityQueue[int]()
+	var model []int
+
+	for i := 0; i < 1000; i++ {
+		if rnd.IntN(3) == 0 && len(model) > 0 {
+			slices.Sort(model)
+			v, ok := q.Pop()
+			assert.True(t, ok)
+			assert.Equal(t, model[0], v)
+			model = model[1:]
+		} else {
+			v := rnd.IntN(100)
+			q.Push(v)
+			model = append(model, v)
+		}
+		assert.Equal(t, len(model), q.Len())
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"iter"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// See https://en.wikipedia.org/wiki/Radix_tree for the data structure.
+
+// RadixTree is a compressed prefix tree that maps string keys to values of type V.
+// Unlike [Trie], chains of nodes that only have a single child are stored as a single edge which
+// uses a lot less memory for long keys like URL paths.
+// Iterating the tree returns the keys in ascending order.
+//
+// A cheap immutable copy of the tree can be made with [RadixTree.Snapshot]. The snapshot shares
+// its nodes with the tree and nodes are only copied once either of the trees are modified.
+// The zero value is not usable, create a new tree using [NewRadixTree].
+type RadixTree[V any] struct {
+	root *radixNode[V]
+	len  int
+	gen  uint64
+}
+
+type radixNode[V any] struct {
+	gen      uint64          // the generation of the tree that is allowed to modify this node
+	prefix   string          // the edge label leading into this node
+	children []*radixNode[V] // sorted by the first byte of the prefix
+	value    V
+	hasValue bool
+}
+
+// radixGeneration is used to hand out unique generations to trees.
+var radixGeneration atomic.Uint64
+
+// NewRadixTree creates a new empty radix tree.
+func NewRadixTree[V any]() *RadixTree[V] {
+	gen := radixGeneration.Add(1)
+	return &RadixTree[V]{
+		root: &radixNode[V]{gen: gen},
+		gen:  gen,
+	}
+}
+
+// Len returns the number of keys stored in the tree.
+func (t *RadixTree[V]) Len() int {
+	return t.len
+}
+
+// Snapshot returns a copy of the tree in O(1) time.
+// The snapshot and the tree can both be modified without affecting each other and it is safe
+// to read from a snapshot while the original tree is being modified by another goroutine.
+func (t *RadixTree[V]) Snapshot() *RadixTree[V] {
+	// Neither tree is allowed to modify the shared nodes from now on
+	t.gen = radixGeneration.Add(1)
+	return &RadixTree[V]{
+		root: t.root,
+		len:  t.len,
+		gen:  radixGeneration.Add(1),
+	}
+}
+
+// Put stores the value for the key.
+// Returns true if the key is new and false if an existing value was replaced.
+func (t *RadixTree[V]) Put(key string, value V) bool {
+	t.root = t.writable(t.root)
+	n := t.root
+	search := key
+
+	for {
+		if len(search) == 0 {
+			added := !n.hasValue
+			n.value = value
+			n.hasValue = true
+			if added {
+				t.len++
+			}
+			return added
+		}
+
+		i, found := n.findChild(search[0])
+		if !found {
+			leaf := &radixNode[V]{gen: t.gen, prefix: search, value: value, hasValue: true}
+			n.children = slices.Insert(n.children, i, leaf)
+			t.len++
+			return true
+		}
+
+		child := t.writable(n.children[i])
+		n.children[i] = child
+
+		common := commonPrefixLen(search, child.prefix)
+		if common == len(child.prefix) {
+			search = search[common:]
+			n = child
+			continue
+		}
+
+		// Split the edge at the point where the key diverges
+		split := &radixNode[V]{gen: t.gen, prefix: search[:common]}
+		child.prefix = child.prefix[common:]
+		split.children = []*radixNode[V]{child}
+		n.children[i] = split
+
+		search = search[common:]
+		if len(search) == 0 {
+			split.value = value
+			split.hasValue = true
+		} else {
+			leaf := &radixNode[V]{gen: t.gen, prefix: search, value: value, hasValue: true}
+			j, _ := split.findChild(search[0])
+			split.children = slices.Insert(split.children, j, leaf)
+		}
+		t.len++
+		return true
+	}
+}
+
+// Get returns the value for the key and true if the key exists.
+func (t *RadixTree[V]) Get(key string) (V, bool) {
+	n := t.root
+	search := key
+	for len(search) > 0 {
+		i, found := n.findChild(search[0])
+		if !found || !strings.HasPrefix(search, n.children[i].prefix) {
+			var zero V
+			return zero, false
+		}
+		n = n.children[i]
+		search = search[len(n.prefix):]
+	}
+	return n.value, n.hasValue
+}
+
+// Contains returns true if the key exists in the tree.
+func (t *RadixTree[V]) Contains(key string) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// Delete removes the key from the tree.
+// Returns true if the key existed before being removed.
+func (t *RadixTree[V]) Delete(key string) bool {
+	if !t.Contains(key) {
+		return false
+	}
+
+	t.root = t.writable(t.root)
+	var grandparent, parent *radixNode[V]
+	grandparentIndex, parentIndex := 0, 0
+	n := t.root
+	search := key
+	for len(search) > 0 {
+		i, _ := n.findChild(search[0])
+		child := t.writable(n.children[i])
+		n.children[i] = child
+		grandparent, grandparentIndex = parent, parentIndex
+		parent, parentIndex, n = n, i, child
+		search = search[len(n.prefix):]
+	}
+
+	var zero V
+	n.value = zero
+	n.hasValue = false
+	t.len--
+
+	if parent == nil {
+		// Deleted the empty key which is stored on the root
+		return true
+	}
+
+	switch len(n.children) {
+	case 0:
+		parent.children = slices.Delete(parent.children, parentIndex, parentIndex+1)
+		// The parent might now only have a single child and can be merged with it
+		if grandparent != nil && !parent.hasValue && len(parent.children) == 1 {
+			grandparent.children[grandparentIndex] = t.merge(parent)
+		}
+	case 1:
+		parent.children[parentIndex] = t.merge(n)
+	}
+
+	return true
+}
+
+// LongestPrefix returns the longest key in the tree that is a prefix of key.
+// Returns false if no key in the tree is a prefix of key.
+func (t *RadixTree[V]) LongestPrefix(key string) (string, V, bool) {
+	var value V
+	length := -1
+
+	t.walkPath(key, func(n *radixNode[V], consumed int) bool {
+		value, length = n.value, consumed
+		return true
+	})
+
+	if length < 0 {
+		return "", value, false
+	}
+	return key[:length], value, true
+}
+
+// WalkPath calls fn for every key in the tree that is a prefix of key (i.e. all the ancestors
+// of key including key itself) starting with the shortest key.
+// The walk stops when fn returns false.
+func (t *RadixTree[V]) WalkPath(key string, fn func(key string, value V) bool) {
+	t.walkPath(key, func(n *radixNode[V], consumed int) bool {
+		return fn(key[:consumed], n.value)
+	})
+}
+
+// WalkPrefix calls fn for every key that starts with prefix in ascending key order.
+// The walk stops when fn returns false.
+func (t *RadixTree[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	for k, v := range t.WithPrefix(prefix) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// WithPrefix returns an iterator over the key-value pairs of every key that starts with prefix
+// in ascending key order.
+func (t *RadixTree[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := t.root
+		path := make([]byte, 0, len(prefix)+32)
+		search := prefix
+		for len(search) > 0 {
+			i, found := n.findChild(search[0])
+			if !found {
+				return
+			}
+			n = n.children[i]
+			switch {
+			case strings.HasPrefix(search, n.prefix):
+				search = search[len(n.prefix):]
+			case strings.HasPrefix(n.prefix, search):
+				search = ""
+			default:
+				return
+			}
+			path = append(path, n.prefix...)
+		}
+		n.walk(path, yield)
+	}
+}
+
+// All returns an iterator over all the key-value pairs in ascending key order.
+func (t *RadixTree[V]) All() iter.Seq2[string, V] {
+	return t.WithPrefix("")
+}
+
+// Keys returns all the keys in ascending order.
+func (t *RadixTree[V]) Keys() []string {
+	result := make([]string, 0, t.Len())
+	for k := range t.All() {
+		result = append(result, k)
+	}
+	return result
+}
+
+//-----------------------------------------------------------------------------
+
+// writable returns n if it is owned by the tree, otherwise a copy of n that is owned by the tree.
+func (t *RadixTree[V]) writable(n *radixNode[V]) *radixNode[V] {
+	if n.gen == t.gen {
+		return n
+	}
+	c := *n
+	c.gen = t.gen
+	c.children = slices.Clone(n.children)
+	return &c
+}
+
+// merge combines the writable node n that has no value with its only child.
+func (t *RadixTree[V]) merge(n *radixNode[V]) *radixNode[V] {
+	child := t.writable(n.children[0])
+	child.prefix = n.prefix + child.prefix
+	return child
+}
+
+// walkPath calls fn for every node with a value along the path of key.
+// consumed is the length of key that the node represents.
+func (t *RadixTree[V]) walkPath(key string, fn func(n *radixNode[V], consumed int) bool) {
+	n := t.root
+	consumed := 0
+	for {
+		if n.hasValue && !fn(n, consumed) {
+			return
+		}
+		search := key[consumed:]
+		if len(search) == 0 {
+			return
+		}
+		i, found := n.findChild(search[0])
+		if !found || !strings.HasPrefix(search, n.children[i].prefix) {
+			return
+		}
+		n = n.children[i]
+		consumed += len(n.prefix)
+	}
+}
+
+// findChild returns the index of the child whose prefix starts with b or the index at which it should be inserted.
+func (n *radixNode[V]) findChild(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].prefix[0] >= b
+	})
+	return i, i < len(n.children) && n.children[i].prefix[0] == b
+}
+
+// walk does a pre-order traversal which visits the keys in ascending order.
+// path is the full key of n. Returns false if the walk was stopped.
+func (n *radixNode[V]) walk(path []byte, yield func(string, V) bool) bool {
+	if n.hasValue && !yield(string(path), n.value) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.walk(append(path, child.prefix...), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPrefixLen returns the length of the common prefix of a and b.
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRadixTreePutGetDelete(t *testing.T) {
+	tr := collection.NewRadixTree[int]()
+	assert.True(t, tr.Put("romane", 1))
+	assert.True(t, tr.Put("romanus", 2))
+	assert.True(t, tr.Put("romulus", 3))
+	assert.True(t, tr.Put("rubens", 4))
+	assert.True(t, tr.Put("ruber", 5))
+	assert.True(t, tr.Put("rom", 6))
+	assert.False(t, tr.Put("rubens", 40))
+	assert.Equal(t, 6, tr.Len())
+
+	v, ok := tr.Get("rubens")
+	assert.True(t, ok)
+	assert.Equal(t, 40, v)
+	assert.True(t, tr.Contains("rom"))
+	assert.False(t, tr.Contains("ro"))
+	assert.False(t, tr.Contains("romanes"))
+
+	assert.Equal(t, []string{"rom", "romane", "romanus", "romulus", "rubens", "ruber"}, tr.Keys())
+
+	assert.True(t, tr.Delete("rom"))
+	assert.False(t, tr.Delete("rom"))
+	assert.False(t, tr.Delete("r"))
+	assert.True(t, tr.Delete("romulus"))
+	assert.True(t, tr.Delete("ruber"))
+	assert.Equal(t, []string{"romane", "romanus", "rubens"}, tr.Keys())
+	assert.Equal(t, 3, tr.Len())
+
+	assert.True(t, tr.Put("", 0))
+	assert.True(t, tr.Delete(""))
+	assert.False(t, tr.Contains(""))
+}
+
+func TestRadixTreeModel(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	tr := collection.NewRadixTree[int]()
+	model := make(map[string]int)
+
+	randomKey := func() string {
+		b := make([]byte, rnd.IntN(6))
+		for i := range b {
+			b[i] = "abc"[rnd.IntN(3)]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 5000; i++ {
+		key := randomKey()
+		if rnd.IntN(3) == 0 {
+			_, exists := model[key]
+			assert.Equal(t, exists, tr.Delete(key), "delete %q", key)
+			delete(model, key)
+		} else {
+			_, exists := model[key]
+			assert.Equal(t, !exists, tr.Put(key, i), "put %q", key)
+			model[key] = i
+		}
+		require.Equal(t, len(model), tr.Len())
+	}
+
+	assert.Equal(t, model, maps.Collect(tr.All()))
+	expectedKeys := slices.Sorted(maps.Keys(model))
+	assert.Equal(t, expectedKeys, tr.Keys())
+}
+
+func TestRadixTreeLongestPrefixAndWalkPath(t *testing.T) {
+	tr := collection.NewRadixTree[string]()
+	tr.Put("/", "root")
+	tr.Put("/api", "api")
+	tr.Put("/api/v1/users", "users")
+	tr.Put("/apix", "apix")
+
+	prefix, v, ok := tr.LongestPrefix("/api/v1/users/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/api/v1/users", prefix)
+	assert.Equal(t, "users", v)
+
+	prefix, v, ok = tr.LongestPrefix("/api/v1")
+	assert.True(t, ok)
+	assert.Equal(t, "/api", prefix)
+	assert.Equal(t, "api", v)
+
+	_, _, ok = tr.LongestPrefix("api")
+	assert.False(t, ok)
+
+	var path []string
+	tr.WalkPath("/api/v1/users/42", func(key string, value string) bool {
+		path = append(path, key)
+		return true
+	})
+	assert.Equal(t, []string{"/", "/api", "/api/v1/users"}, path)
+
+	path = nil
+	tr.WalkPath("/api/v1/users", func(key string, value string) bool {
+		path = append(path, key)
+		return len(path) < 2
+	})
+	assert.Equal(t, []string{"/", "/api"}, path)
+}
+
+func TestRadixTreeWithPrefix(t *testing.T) {
+	tr := collection.NewRadixTree[int]()
+	for i, k := range []string{"/api/users", "/api/groups", "/apix", "/web/index", "/api"} {
+		tr.Put(k, i)
+	}
+
+	collect := func(prefix string) []string {
+		var keys []string
+		tr.WalkPrefix(prefix, func(key string, _ int) bool {
+			keys = append(keys, key)
+			return true
+		})
+		return keys
+	}
+
+	assert.Equal(t, []string{"/api", "/api/groups", "/api/users", "/apix"}, collect("/ap"))
+	assert.Equal(t, []string{"/api/groups", "/api/users"}, collect("/api/"))
+	assert.Equal(t, []string{"/api/users"}, collect("/api/u"))
+	assert.Empty(t, collect("/api/x"))
+	assert.Empty(t, collect("/z"))
+	assert.Len(t, collect(""), 5)
+}
+
+func TestRadixTreeSnapshot(t *testing.T) {
+	tr := collection.NewRadixTree[int]()
+	for i := 0; i < 100; i++ {
+		tr.Put(fmt.Sprintf("key/%03d", i), i)
+	}
+
+	snap := tr.Snapshot()
+	tr.Put("key/100", 100)
+	tr.Put("key/000", -1)
+	tr.Delete("key/050")
+
+	assert.Equal(t, 100, snap.Len())
+	v, _ := snap.Get("key/000")
+	assert.Equal(t, 0, v)
+	assert.True(t, snap.Contains("key/050"))
+	assert.False(t, snap.Contains("key/100"))
+
+	assert.Equal(t, 100, tr.Len())
+	v, _ = tr.Get("key/000")
+	assert.Equal(t, -1, v)
+
+	// The snapshot can also be modified without affecting the original
+	snap.Delete("key/001")
+	assert.True(t, tr.Contains("key/001"))
+}
+
+func TestRadixTreeSnapshotConcurrentRead(t *testing.T) {
+	tr := collection.NewRadixTree[int]()
+	for i := 0; i < 1000; i++ {
+		tr.Put(fmt.Sprintf("%d", i), i)
+	}
+	snap := tr.Snapshot()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			tr.Put(fmt.Sprintf("%d", i), -i)
+			tr.Delete(fmt.Sprintf("%d", i/2))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			v, ok := snap.Get(fmt.Sprintf("%d", i))
+			assert.True(t, ok)
+			assert.Equal(t, i, v)
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkRadixTree(b *testing.B) {
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("/api/v1/resources/%d/details", i)
+	}
+
+	b.Run("Put", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tr := collection.NewRadixTree[int]()
+			for j, k := range keys {
+				tr.Put(k, j)
+			}
+		}
+	})
+
+	tr := collection.NewRadixTree[int]()
+	for j, k := range keys {
+		tr.Put(k, j)
+	}
+
+	b.Run("LongestPrefix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = tr.LongestPrefix(keys[i%len(keys)] + "/extra")
+		}
+	})
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"iter"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Range is a closed range of integers [Lo, Hi].
+type Range[T Integer] struct {
+	Lo T
+	Hi T
+}
+
+// Contains returns true if v is within the range.
+func (r Range[T]) Contains(v T) bool {
+	return r.Lo <= v && v <= r.Hi
+}
+
+// RangeSet is a set of integers that is stored as a sorted slice of disjoint ranges.
+// Adjacent and overlapping ranges are coalesced when inserted, which makes it a compact
+// alternative to [Set] for things like port ranges, disk extents and sequence numbers.
+// The zero value is an empty set ready to use.
+type RangeSet[T Integer] struct {
+	ranges []Range[T]
+}
+
+// NewRangeSet creates a new empty range set.
+func NewRangeSet[T Integer]() *RangeSet[T] {
+	return &RangeSet[T]{}
+}
+
+// NewRangeSetFrom creates a new range set that contains all the integers from the ranges.
+// Ranges where Lo > Hi are ignored.
+func NewRangeSetFrom[T Integer](ranges ...Range[T]) *RangeSet[T] {
+	s := NewRangeSet[T]()
+	for _, r := range ranges {
+		s.InsertRange(r.Lo, r.Hi)
+	}
+	return s
+}
+
+// Len returns the number of integers in the set.
+// If the number of integers does not fit in an int then math.MaxInt is returned.
+func (s *RangeSet[T]) Len() int {
+	var total uint64
+	for _, r := range s.ranges {
+		n := uint64(r.Hi) - uint64(r.Lo) + 1
+		if n == 0 || total+n < total || total+n > math.MaxInt {
+			return math.MaxInt
+		}
+		total += n
+	}
+	return int(total)
+}
+
+// RangeCount returns the number of disjoint ranges used to store the set.
+func (s *RangeSet[T]) RangeCount() int {
+	return len(s.ranges)
+}
+
+// Ranges returns a copy of the disjoint ranges in ascending order.
+func (s *RangeSet[T]) Ranges() []Range[T] {
+	return slices.Clone(s.ranges)
+}
+
+// All returns an iterator over every integer in the set in ascending order.
+func (s *RangeSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, r := range s.ranges {
+			for v := r.Lo; ; v++ {
+				if !yield(v) {
+					return
+				}
+				if v == r.Hi {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Insert a single integer into the set.
+// Returns true if the integer could be inserted and false if it is already in the set.
+func (s *RangeSet[T]) Insert(v T) bool {
+	if s.Contains(v) {
+		return false
+	}
+	s.InsertRange(v, v)
+	return true
+}
+
+// InsertRange inserts all the integers in the closed range [lo, hi] into the set.
+// Nothing is inserted if lo > hi.
+func (s *RangeSet[T]) InsertRange(lo T, hi T) {
+	if lo > hi {
+		return
+	}
+
+	// First range that overlaps or is adjacent to lo
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		r := s.ranges[i]
+		return r.Hi >= lo || r.Hi+1 == lo
+	})
+	// First range that starts after hi and is not adjacent to it
+	j := sort.Search(len(s.ranges), func(j int) bool {
+		r := s.ranges[j]
+		return r.Lo > hi && r.Lo-1 != hi
+	})
+
+	merged := Range[T]{Lo: lo, Hi: hi}
+	if i < j {
+		merged.Lo = min(lo, s.ranges[i].Lo)
+		merged.Hi = max(hi, s.ranges[j-1].Hi)
+	}
+	s.ranges = slices.Replace(s.ranges, i, j, merged)
+}
+
+// Remove the integer from the set.
+// Returns true if the integer was in the set before removing.
+func (s *RangeSet[T]) Remove(v T) bool {
+	if !s.Contains(v) {
+		return false
+	}
+	s.RemoveRange(v, v)
+	return true
+}
+
+// RemoveRange removes all the integers in the closed range [lo, hi] from the set.
+// Ranges that are only partially covered are split. Nothing is removed if lo > hi.
+func (s *RangeSet[T]) RemoveRange(lo T, hi T) {
+	if lo > hi {
+		return
+	}
+
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].Hi >= lo
+	})
+	j := sort.Search(len(s.ranges), func(j int) bool {
+		return s.ranges[j].Lo > hi
+	})
+	if i >= j {
+		return
+	}
+
+	remainder := make([]Range[T], 0, 2)
+	if first := s.ranges[i]; first.Lo < lo {
+		remainder = append(remainder, Range[T]{Lo: first.Lo, Hi: lo - 1})
+	}
+	if last := s.ranges[j-1]; last.Hi > hi {
+		remainder = append(remainder, Range[T]{Lo: hi + 1, Hi: last.Hi})
+	}
+	s.ranges = slices.Replace(s.ranges, i, j, remainder...)
+}
+
+// Contains returns true if the integer is in the set.
+func (s *RangeSet[T]) Contains(v T) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].Hi >= v
+	})
+	return i < len(s.ranges) && s.ranges[i].Lo <= v
+}
+
+// ContainsRange returns true if all the integers in the closed range [lo, hi] are in the set.
+func (s *RangeSet[T]) ContainsRange(lo T, hi T) bool {
+	if lo > hi {
+		return false
+	}
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].Hi >= lo
+	})
+	return i < len(s.ranges) && s.ranges[i].Lo <= lo && hi <= s.ranges[i].Hi
+}
+
+// Gaps returns the ranges within the closed range [lo, hi] that are not in the set.
+func (s *RangeSet[T]) Gaps(lo T, hi T) []Range[T] {
+	result := make([]Range[T], 0)
+	if lo > hi {
+		return result
+	}
+
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].Hi >= lo
+	})
+
+	cursor := lo
+	for ; i < len(s.ranges) && s.ranges[i].Lo <= hi; i++ {
+		r := s.ranges[i]
+		if r.Lo > cursor {
+			result = append(result, Range[T]{Lo: cursor, Hi: r.Lo - 1})
+		}
+		if r.Hi >= hi {
+			return result
+		}
+		cursor = r.Hi + 1
+	}
+
+	return append(result, Range[T]{Lo: cursor, Hi: hi})
+}
+
+// Equal returns true if both sets contain exactly the same integers.
+func (a *RangeSet[T]) Equal(b *RangeSet[T]) bool {
+	return slices.Equal(a.ranges, b.ranges)
+}
+
+// Clone returns a copy of the set.
+func (s *RangeSet[T]) Clone() *RangeSet[T] {
+	return &RangeSet[T]{
+		ranges: slices.Clone(s.ranges),
+	}
+}
+
+// Return a new set that is the union of this set and another.
+func (a *RangeSet[T]) Union(b *RangeSet[T]) *RangeSet[T] {
+	c := a.Clone()
+	for _, r := range b.ranges {
+		c.InsertRange(r.Lo, r.Hi)
+	}
+	return c
+}
+
+// Return a new set that contains only the integers that are present in both sets.
+func (a *RangeSet[T]) Intersection(b *RangeSet[T]) *RangeSet[T] {
+	c := NewRangeSet[T]()
+	i, j := 0, 0
+	for i < len(a.ranges) && j < len(b.ranges) {
+		ra, rb := a.ranges[i], b.ranges[j]
+		lo := max(ra.Lo, rb.Lo)
+		hi := min(ra.Hi, rb.Hi)
+		if lo <= hi {
+			c.ranges = append(c.ranges, Range[T]{Lo: lo, Hi: hi})
+		}
+		if ra.Hi < rb.Hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return c
+}
+
+// Return a new set that contains only the integers that are present in this set but not in b.
+func (a *RangeSet[T]) Difference(b *RangeSet[T]) *RangeSet[T] {
+	c := a.Clone()
+	for _, r := range b.ranges {
+		c.RemoveRange(r.Lo, r.Hi)
+	}
+	return c
+}
+
+// Return a new set that contains only the integers that are present in one or the other set but not the integers that appear in both sets.
+func (a *RangeSet[T]) SymmetricDifference(b *RangeSet[T]) *RangeSet[T] {
+	return a.Difference(b).Union(b.Difference(a))
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type intRange = collection.Range[int]
+
+func TestRangeSetInsertCoalesces(t *testing.T) {
+	s := collection.NewRangeSet[int]()
+	s.InsertRange(10, 20)
+	s.InsertRange(30, 40)
+	assert.Equal(t, []intRange{{10, 20}, {30, 40}}, s.Ranges())
+
+	s.InsertRange(21, 29)
+	assert.Equal(t, []intRange{{10, 40}}, s.Ranges())
+
+	s.InsertRange(50, 60)
+	s.InsertRange(0, 5)
+	s.InsertRange(3, 55)
+	assert.Equal(t, []intRange{{0, 60}}, s.Ranges())
+
+	assert.True(t, s.Insert(61))
+	assert.False(t, s.Insert(61))
+	assert.True(t, s.Insert(-1))
+	assert.Equal(t, []intRange{{-1, 61}}, s.Ranges())
+	assert.Equal(t, 63, s.Len())
+	assert.Equal(t, 1, s.RangeCount())
+
+	s.InsertRange(100, 90)
+	assert.Equal(t, 1, s.RangeCount())
+}
+
+func TestRangeSetRemoveSplits(t *testing.T) {
+	s := collection.NewRangeSetFrom(intRange{0, 100})
+	s.RemoveRange(10, 19)
+	assert.Equal(t, []intRange{{0, 9}, {20, 100}}, s.Ranges())
+
+	assert.True(t, s.Remove(50))
+	assert.False(t, s.Remove(50))
+	assert.Equal(t, []intRange{{0, 9}, {20, 49}, {51, 100}}, s.Ranges())
+
+	s.RemoveRange(5, 60)
+	assert.Equal(t, []intRange{{0, 4}, {61, 100}}, s.Ranges())
+
+	s.RemoveRange(-10, 200)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestRangeSetContains(t *testing.T) {
+	s := collection.NewRangeSetFrom(intRange{0, 9}, intRange{20, 29})
+	assert.True(t, s.Contains(0))
+	assert.True(t, s.Contains(9))
+	assert.False(t, s.Contains(10))
+	assert.False(t, s.Contains(-1))
+	assert.True(t, s.ContainsRange(21, 29))
+	assert.False(t, s.ContainsRange(5, 25))
+	assert.False(t, s.ContainsRange(9, 5))
+}
+
+func TestRangeSetGaps(t *testing.T) {
+	s := collection.NewRangeSetFrom(intRange{10, 19}, intRange{30, 39})
+	assert.Equal(t, []intRange{{0, 9}, {20, 29}, {40, 50}}, s.Gaps(0, 50))
+	assert.Equal(t, []intRange{{20, 29}}, s.Gaps(15, 35))
+	assert.Equal(t, []intRange{}, s.Gaps(12, 18))
+	assert.Equal(t, []intRange{{100, 200}}, s.Gaps(100, 200))
+	assert.Equal(t, []intRange{}, s.Gaps(10, 0))
+}
+
+func TestRangeSetAlgebra(t *testing.T) {
+	a := collection.NewRangeSetFrom(intRange{0, 10}, intRange{20, 30})
+	b := collection.NewRangeSetFrom(intRange{5, 25}, intRange{40, 50})
+
+	assert.Equal(t, []intRange{{0, 30}, {40, 50}}, a.Union(b).Ranges())
+	assert.Equal(t, []intRange{{5, 10}, {20, 25}}, a.Intersection(b).Ranges())
+	assert.Equal(t, []intRange{{0, 4}, {26, 30}}, a.Difference(b).Ranges())
+	assert.Equal(t, []intRange{{0, 4}, {11, 19}, {26, 30}, {40, 50}}, a.SymmetricDifference(b).Ranges())
+
+	// The inputs are not modified
+	assert.Equal(t, []intRange{{0, 10}, {20, 30}}, a.Ranges())
+	assert.True(t, a.Union(a).Equal(a))
+	assert.Equal(t, 0, a.Difference(a).Len())
+}
+
+func TestRangeSetIntegerLimits(t *testing.T) {
+	s := collection.NewRangeSet[uint8]()
+	s.InsertRange(0, 10)
+	s.InsertRange(250, 255)
+	s.InsertRange(11, 249)
+	assert.Equal(t, []collection.Range[uint8]{{0, 255}}, s.Ranges())
+	assert.Equal(t, 256, s.Len())
+	assert.Equal(t, []collection.Range[uint8]{}, s.Gaps(0, 255))
+
+	s.RemoveRange(255, 255)
+	s.RemoveRange(0, 0)
+	assert.Equal(t, []collection.Range[uint8]{{1, 254}}, s.Ranges())
+
+	signed := collection.NewRangeSetFrom(collection.Range[int8]{-128, -1}, collection.Range[int8]{0, 127})
+	assert.Equal(t, []collection.Range[int8]{{-128, 127}}, signed.Ranges())
+
+	huge := collection.NewRangeSetFrom(collection.Range[uint64]{0, math.MaxUint64})
+	assert.Equal(t, math.MaxInt, huge.Len())
+}
+
+func TestRangeSetAll(t *testing.T) {
+	s := collection.NewRangeSetFrom(intRange{1, 3}, intRange{7, 8})
+	assert.Equal(t, []int{1, 2, 3, 7, 8}, slices.Collect(s.All()))
+
+	var zero collection.RangeSet[int]
+	zero.Insert(5)
+	assert.Equal(t, []int{5}, slices.Collect(zero.All()))
+}
+
+func TestRangeSetModel(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	s := collection.NewRangeSet[int]()
+	model := collection.NewSet[int]()
+
+	for i := 0; i < 2000; i++ {
+		lo := rnd.IntN(500)
+		hi := lo + rnd.IntN(30)
+		if rnd.IntN(2) == 0 {
+			s.InsertRange(lo, hi)
+			for v := lo; v <= hi; v++ {
+				model.Insert(v)
+			}
+		} else {
+			s.RemoveRange(lo, hi)
+			for v := lo; v <= hi; v++ {
+				model.Remove(v)
+			}
+		}
+
+		require.Equal(t, model.Len(), s.Len())
+		ranges := s.Ranges()
+		for k := 1; k < len(ranges); k++ {
+			require.Greater(t, ranges[k].Lo, ranges[k-1].Hi+1, "ranges must be disjoint and not adjacent")
+		}
+	}
+
+	items := model.Items()
+	slices.Sort(items)
+	assert.Equal(t, items, slices.Collect(s.All()))
+}
+
+func BenchmarkRangeSet(b *testing.B) {
+	b.Run("RangeSet", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := collection.NewRangeSet[int]()
+			for v := 0; v < 10000; v++ {
+				s.Insert(v)
+			}
+		}
+	})
+
+	b.Run("Set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := collection.NewSet[int]()
+			for v := 0; v < 10000; v++ {
+				s.Insert(v)
+			}
+		}
+	})
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"iter"
+	"maps"
+)
+
+// ReadOnlySet is a view of a set that only exposes the methods that don't modify the set.
+// APIs can return a ReadOnlySet to hand out a set that the caller is not allowed to change.
+// Use [Set.AsReadOnly] to create a view of a [Set].
+// The set algebra methods take another ReadOnlySet so that views can be combined without cloning them first.
+type ReadOnlySet[T comparable] interface {
+	// Len returns the number of items stored in the set.
+	Len() int
+	// Contains returns true if the item is in the set.
+	Contains(item T) bool
+	// ContainsSlice returns true if all items in the slice are present in the set.
+	ContainsSlice(items []T) bool
+	// Items returns a new slice that contains the items stored in the set.
+	Items() []T
+	// All returns an iterator over all the items in the set.
+	All() iter.Seq[T]
+	// Clone returns a new mutable set that contains the same items.
+	Clone() Set[T]
+	// Union returns a new set that is the union of this set and b.
+	Union(b ReadOnlySet[T]) Set[T]
+	// Intersection returns a new set that contains only the items that are present in both sets.
+	Intersection(b ReadOnlySet[T]) Set[T]
+	// Difference returns a new set that contains only the items that are present in this set but not in b.
+	Difference(b ReadOnlySet[T]) Set[T]
+	// SymmetricDifference returns a new set that contains only the items that are present in one set but not both.
+	SymmetricDifference(b ReadOnlySet[T]) Set[T]
+}
+
+// ReadOnlyMap is a view of a map that only exposes the methods that don't modify the map.
+// APIs can return a ReadOnlyMap to hand out a map that the caller is not allowed to change.
+// Use [MapAsReadOnly] to create a view of a Go map.
+// The map algebra methods take another ReadOnlyMap so that views can be combined without cloning them first.
+type ReadOnlyMap[K comparable, V any] interface {
+	// Len returns the number of key-value pairs.
+	Len() int
+	// Get returns the value for the key and true if the key exists.
+	Get(key K) (V, bool)
+	// Contains returns true if the key exists.
+	Contains(key K) bool
+	// Keys returns a new slice that contains all the keys.
+	Keys() []K
+	// Values returns a new slice that contains all the values.
+	Values() []V
+	// All returns an iterator over all the key-value pairs.
+	All() iter.Seq2[K, V]
+	// Clone returns a new map that contains the same key-value pairs.
+	Clone() map[K]V
+	// Union returns a new map that contains the keys from both maps, preferring the values of this map.
+	Union(b ReadOnlyMap[K, V]) map[K]V
+	// Intersection returns a new map that contains only the keys that are present in both maps.
+	Intersection(b ReadOnlyMap[K, V]) map[K]V
+	// Difference returns a new map that contains only the keys that are present in this map but not in b.
+	Difference(b ReadOnlyMap[K, V]) map[K]V
+	// SymmetricDifference returns a new map that contains only the keys that are present in one map but not both.
+	SymmetricDifference(b ReadOnlyMap[K, V]) map[K]V
+}
+
+// MapAsReadOnly returns a read-only view of the map.
+// The view reflects changes made to the map but can't be used to modify it.
+func MapAsReadOnly[K comparable, V any](m map[K]V) ReadOnlyMap[K, V] {
+	return readOnlyMap[K, V]{m: m}
+}
+
+//-----------------------------------------------------------------------------
+
+// readOnlySet wraps a Set so that callers can't type assert the view back into a mutable Set.
+type readOnlySet[T comparable] struct {
+	s Set[T]
+}
+
+func (r readOnlySet[T]) Len() int {
+	return r.s.Len()
+}
+
+func (r readOnlySet[T]) Contains(item T) bool {
+	return r.s.Contains(item)
+}
+
+func (r readOnlySet[T]) ContainsSlice(items []T) bool {
+	return r.s.ContainsSlice(items)
+}
+
+func (r readOnlySet[T]) Items() []T {
+	return r.s.Items()
+}
+
+func (r readOnlySet[T]) All() iter.Seq[T] {
+	return r.s.All()
+}
+
+func (r readOnlySet[T]) Clone() Set[T] {
+	return r.s.Clone()
+}
+
+func (r readOnlySet[T]) Union(b ReadOnlySet[T]) Set[T] {
+	return r.s.Union(setOf(b))
+}
+
+func (r readOnlySet[T]) Intersection(b ReadOnlySet[T]) Set[T] {
+	return r.s.Intersection(setOf(b))
+}
+
+func (r readOnlySet[T]) Difference(b ReadOnlySet[T]) Set[T] {
+	return r.s.Difference(setOf(b))
+}
+
+func (r readOnlySet[T]) SymmetricDifference(b ReadOnlySet[T]) Set[T] {
+	return r.s.SymmetricDifference(setOf(b))
+}
+
+// setOf returns the set behind the view or a copy of the items for other implementations.
+func setOf[T comparable](r ReadOnlySet[T]) Set[T] {
+	if view, ok := r.(readOnlySet[T]); ok {
+		return view.s
+	}
+	return NewSetFrom(r.Items())
+}
+
+// readOnlyMap wraps a Go map so that callers can't type assert the view back into the map.
+type readOnlyMap[K comparable, V any] struct {
+	m map[K]V
+}
+
+func (r readOnlyMap[K, V]) Len() int {
+	return len(r.m)
+}
+
+func (r readOnlyMap[K, V]) Get(key K) (V, bool) {
+	v, ok := r.m[key]
+	return v, ok
+}
+
+func (r readOnlyMap[K, V]) Contains(key K) bool {
+	_, ok := r.m[key]
+	return ok
+}
+
+func (r readOnlyMap[K, V]) Keys() []K {
+	result := make([]K, 0, len(r.m))
+	for k := range r.m {
+		result = append(result, k)
+	}
+	return result
+}
+
+func (r readOnlyMap[K, V]) Values() []V {
+	result := make([]V, 0, len(r.m))
+	for _, v := range r.m {
+		result = append(result, v)
+	}
+	return result
+}
+
+func (r readOnlyMap[K, V]) All() iter.Seq2[K, V] {
+	return maps.All(r.m)
+}
+
+func (r readOnlyMap[K, V]) Clone() map[K]V {
+	result := maps.Clone(r.m)
+	if result == nil {
+		result = make(map[K]V)
+	}
+	return result
+}
+
+func (r readOnlyMap[K, V]) Union(b ReadOnlyMap[K, V]) map[K]V {
+	return MapUnion(r.m, mapOf(b))
+}
+
+func (r readOnlyMap[K, V]) Intersection(b ReadOnlyMap[K, V]) map[K]V {
+	return MapIntersection(r.m, mapOf(b))
+}
+
+func (r readOnlyMap[K, V]) Difference(b ReadOnlyMap[K, V]) map[K]V {
+	return MapDifference(r.m, mapOf(b))
+}
+
+func (r readOnlyMap[K, V]) SymmetricDifference(b ReadOnlyMap[K, V]) map[K]V {
+	return MapSymmetricDifference(r.m, mapOf(b))
+}
+
+// mapOf returns the map behind the view or a copy of the key-value pairs for other implementations.
+func mapOf[K comparable, V any](r ReadOnlyMap[K, V]) map[K]V {
+	if view, ok := r.(readOnlyMap[K, V]); ok {
+		return view.m
+	}
+	return maps.Collect(r.All())
+}
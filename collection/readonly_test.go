@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlySet(t *testing.T) {
+	s := collection.NewSetFrom([]int{1, 3, 5})
+	r := s.AsReadOnly()
+
+	assert.Equal(t, 3, r.Len())
+	assert.True(t, r.Contains(3))
+	assert.False(t, r.Contains(4))
+	assert.True(t, r.ContainsSlice([]int{1, 5}))
+	assert.False(t, r.ContainsSlice([]int{1, 4}))
+	assert.ElementsMatch(t, []int{1, 3, 5}, r.Items())
+
+	count := 0
+	for range r.All() {
+		count++
+	}
+	assert.Equal(t, 3, count)
+
+	// The view can't be turned back into a mutable set
+	_, ok := any(r).(collection.Set[int])
+	assert.False(t, ok)
+
+	// The view reflects changes made to the underlying set
+	s.Insert(7)
+	assert.True(t, r.Contains(7))
+
+	// Cloning and set algebra return new sets that don't affect the original
+	c := r.Clone()
+	c.Insert(100)
+	assert.False(t, r.Contains(100))
+
+	b := collection.NewSetFrom([]int{3, 4}).AsReadOnly()
+	assert.ElementsMatch(t, []int{1, 3, 4, 5, 7}, r.Union(b).Items())
+	assert.ElementsMatch(t, []int{3}, r.Intersection(b).Items())
+	assert.ElementsMatch(t, []int{1, 5, 7}, r.Difference(b).Items())
+	assert.ElementsMatch(t, []int{1, 4, 5, 7}, r.SymmetricDifference(b).Items())
+	assert.Equal(t, 4, r.Len())
+}
+
+// otherReadOnlySet is a ReadOnlySet that is not created by Set.AsReadOnly.
+type otherReadOnlySet struct {
+	collection.ReadOnlySet[int]
+}
+
+func TestReadOnlySetAlgebraWithOtherImplementations(t *testing.T) {
+	r := collection.NewSetFrom([]int{1, 3, 5}).AsReadOnly()
+	b := otherReadOnlySet{collection.NewSetFrom([]int{3, 4}).AsReadOnly()}
+	assert.ElementsMatch(t, []int{1, 3, 4, 5}, r.Union(b).Items())
+	assert.ElementsMatch(t, []int{3}, r.Intersection(b).Items())
+	assert.ElementsMatch(t, []int{1, 5}, r.Difference(b).Items())
+	assert.ElementsMatch(t, []int{1, 4, 5}, r.SymmetricDifference(b).Items())
+	assert.ElementsMatch(t, []int{3, 4}, b.Items())
+}
+
+func TestReadOnlyMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	r := collection.MapAsReadOnly(m)
+
+	assert.Equal(t, 2, r.Len())
+	v, ok := r.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	_, ok = r.Get("z")
+	assert.False(t, ok)
+	assert.True(t, r.Contains("a"))
+	assert.False(t, r.Contains("z"))
+	assert.ElementsMatch(t, []string{"a", "b"}, r.Keys())
+	assert.ElementsMatch(t, []int{1, 2}, r.Values())
+	assert.Equal(t, m, maps.Collect(r.All()))
+
+	m["c"] = 3
+	assert.Equal(t, 3, r.Len())
+
+	c := r.Clone()
+	c["d"] = 4
+	assert.False(t, r.Contains("d"))
+
+	b := collection.MapAsReadOnly(map[string]int{"c": 30, "e": 5})
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3, "e": 5}, r.Union(b))
+	assert.Equal(t, map[string]int{"c": 3}, r.Intersection(b))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, r.Difference(b))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "e": 5}, r.SymmetricDifference(b))
+	assert.Equal(t, 3, r.Len())
+
+	empty := collection.MapAsReadOnly[string, int](nil)
+	assert.Equal(t, 0, empty.Len())
+	assert.NotNil(t, empty.Clone())
+
+	// Any other ReadOnlyMap implementation can be used as the operand
+	other := struct {
+		collection.ReadOnlyMap[string, int]
+	}{b}
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3, "e": 5}, r.Union(other))
+	assert.Equal(t, map[string]int{"c": 30, "e": 5}, empty.Union(other))
+}
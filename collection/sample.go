@@ -0,0 +1,239 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// See https://en.wikipedia.org/wiki/Reservoir_sampling for the algorithms used.
+
+// Reservoir keeps a uniform random sample of at most k items from a stream of unknown length.
+// It uses Algorithm L which only needs to draw random numbers when an item is selected,
+// making Add very cheap once the reservoir is full.
+// A Reservoir is not safe for concurrent use.
+type Reservoir[T any] struct {
+	rnd   *rand.Rand
+	k     int
+	items []T
+	seen  int
+	w     float64
+	next  int
+}
+
+// NewReservoir creates a new reservoir that will keep a sample of at most k items.
+// The random source rnd is used to make the sampling reproducible. If rnd is nil then
+// the global random source from math/rand/v2 will be used.
+func NewReservoir[T any](k int, rnd *rand.Rand) *Reservoir[T] {
+	r := &Reservoir[T]{
+		rnd: rnd,
+		k:   max(k, 0),
+	}
+	r.Reset()
+	return r
+}
+
+// Add an item from the stream to the reservoir.
+func (r *Reservoir[T]) Add(item T) {
+	r.seen++
+	if r.k == 0 {
+		return
+	}
+
+	if len(r.items) < r.k {
+		r.items = append(r.items, item)
+		if len(r.items) == r.k {
+			r.skip()
+		}
+		return
+	}
+
+	if r.seen == r.next {
+		r.items[randIntN(r.rnd, r.k)] = item
+		r.skip()
+	}
+}
+
+// AddSeq adds all of the items from the iterator to the reservoir.
+func (r *Reservoir[T]) AddSeq(seq iter.Seq[T]) {
+	for item := range seq {
+		r.Add(item)
+	}
+}
+
+// Items returns a copy of the currently sampled items.
+func (r *Reservoir[T]) Items() []T {
+	return slices.Clone(r.items)
+}
+
+// Len returns the number of items currently in the reservoir.
+func (r *Reservoir[T]) Len() int {
+	return len(r.items)
+}
+
+// Seen returns the number of items that have been added to the reservoir.
+func (r *Reservoir[T]) Seen() int {
+	return r.seen
+}
+
+// Reset removes all items from the reservoir so that it can be used for a new stream.
+func (r *Reservoir[T]) Reset() {
+	// Grow the items as needed instead of allocating k up front, k can be much larger than the stream
+	clear(r.items)
+	r.items = r.items[:0]
+	r.seen = 0
+	r.w = math.Exp(math.Log(randFloat64(r.rnd)) / float64(r.k))
+	r.next = 0
+}
+
+// skip calculates the position of the next item in the stream that will replace an item in the reservoir.
+func (r *Reservoir[T]) skip() {
+	r.next = r.seen + int(math.Floor(math.Log(randFloat64(r.rnd))/math.Log(1-r.w))) + 1
+	r.w *= math.Exp(math.Log(randFloat64(r.rnd)) / float64(r.k))
+}
+
+// SampleSlice returns k uniformly chosen items from the slice without replacement.
+// If k >= len(s) then a shuffled copy of the whole slice is returned.
+// The slice is not modified. Runs in O(k) time and memory.
+// If rnd is nil then the global random source from math/rand/v2 will be used.
+func SampleSlice[T any](s []T, k int, rnd *rand.Rand) []T {
+	n := len(s)
+	k = min(max(k, 0), n)
+	result := make([]T, k)
+
+	// Partial Fisher-Yates shuffle that records the swaps instead of modifying s
+	swaps := make(map[int]int, k)
+	lookup := func(i int) int {
+		if v, ok := swaps[i]; ok {
+			return v
+		}
+		return i
+	}
+
+	for i := 0; i < k; i++ {
+		j := i + randIntN(rnd, n-i)
+		vi, vj := lookup(i), lookup(j)
+		swaps[j] = vi
+		result[i] = s[vj]
+	}
+
+	return result
+}
+
+// SampleSeq returns at most k uniformly chosen items from the iterator using reservoir sampling.
+// If rnd is nil then the global random source from math/rand/v2 will be used.
+func SampleSeq[T any](seq iter.Seq[T], k int, rnd *rand.Rand) []T {
+	r := NewReservoir[T](k, rnd)
+	r.AddSeq(seq)
+	return r.items
+}
+
+// WeightedSample returns at most k items from the slice without replacement where the probability
+// of an item being chosen is proportional to the weight returned by the weight function.
+// Items with a weight <= 0 are never chosen.
+// This uses the A-Res algorithm by Efraimidis and Spirakis.
+// If rnd is nil then the global random source from math/rand/v2 will be used.
+func WeightedSample[T any](s []T, k int, weight func(item T) float64, rnd *rand.Rand) []T {
+	return WeightedSampleSeq(slices.Values(s), k, weight, rnd)
+}
+
+// WeightedSampleSeq returns at most k items from the iterator without replacement where the probability
+// of an item being chosen is proportional to the weight returned by the weight function.
+// Items with a weight <= 0 are never chosen.
+// If rnd is nil then the global random source from math/rand/v2 will be used.
+func WeightedSampleSeq[T any](seq iter.Seq[T], k int, weight func(item T) float64, rnd *rand.Rand) []T {
+	if k <= 0 {
+		return []T{}
+	}
+
+	h := &weightedHeap[T]{}
+	for item := range seq {
+		w := weight(item)
+		if !(w > 0) {
+			continue
+		}
+
+		// key = u^(1/w), compared in log space to avoid underflow with small weights
+		key := math.Log(randFloat64(rnd)) / w
+		if h.Len() < k {
+			heap.Push(h, weightedItem[T]{item: item, key: key})
+		} else if key > (*h)[0].key {
+			(*h)[0] = weightedItem[T]{item: item, key: key}
+			heap.Fix(h, 0)
+		}
+	}
+
+	// Return the items with the highest keys first
+	result := make([]T, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(weightedItem[T]).item
+	}
+	return result
+}
+
+//-----------------------------------------------------------------------------
+
+type weightedItem[T any] struct {
+	item T
+	key  float64
+}
+
+// weightedHeap is a min-heap ordered by key.
+type weightedHeap[T any] []weightedItem[T]
+
+func (h weightedHeap[T]) Len() int           { return len(h) }
+func (h weightedHeap[T]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h weightedHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *weightedHeap[T]) Push(x any)        { *h = append(*h, x.(weightedItem[T])) }
+func (h *weightedHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// randIntN returns a random int in [0, n) using rnd or the global source if rnd is nil.
+func randIntN(rnd *rand.Rand, n int) int {
+	if rnd == nil {
+		return rand.IntN(n)
+	}
+	return rnd.IntN(n)
+}
+
+// randFloat64 returns a random float64 in (0, 1) using rnd or the global source if rnd is nil.
+// Zero is excluded so that the result can safely be passed to math.Log.
+func randFloat64(rnd *rand.Rand) float64 {
+	for {
+		var f float64
+		if rnd == nil {
+			f = rand.Float64()
+		} else {
+			f = rnd.Float64()
+		}
+		if f > 0 {
+			return f
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservoir(t *testing.T) {
+	r := collection.NewReservoir[int](5, rand.New(rand.NewPCG(1, 2)))
+	for i := 0; i < 3; i++ {
+		r.Add(i)
+	}
+	assert.Equal(t, []int{0, 1, 2}, r.Items())
+
+	for i := 3; i < 1000; i++ {
+		r.Add(i)
+	}
+	assert.Equal(t, 5, r.Len())
+	assert.Equal(t, 1000, r.Seen())
+	assert.Equal(t, 5, collection.NewSetFrom(r.Items()).Len())
+
+	r.Reset()
+	assert.Equal(t, 0, r.Len())
+	assert.Equal(t, 0, r.Seen())
+
+	empty := collection.NewReservoir[int](0, nil)
+	empty.Add(1)
+	assert.Equal(t, 0, empty.Len())
+}
+
+func TestReservoirIsUniform(t *testing.T) {
+	const n = 20
+	const k = 5
+	const runs = 20000
+
+	rnd := rand.New(rand.NewPCG(42, 42))
+	counts := make([]int, n)
+	for run := 0; run < runs; run++ {
+		r := collection.NewReservoir[int](k, rnd)
+		for i := 0; i < n; i++ {
+			r.Add(i)
+		}
+		for _, item := range r.Items() {
+			counts[item]++
+		}
+	}
+
+	expected := float64(runs*k) / n
+	for i, c := range counts {
+		assert.InEpsilon(t, expected, float64(c), 0.05, "item %d", i)
+	}
+}
+
+func TestSampleSlice(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	original := slices.Clone(s)
+
+	sample := collection.SampleSlice(s, 4, rand.New(rand.NewPCG(1, 2)))
+	assert.Len(t, sample, 4)
+	assert.Equal(t, 4, collection.NewSetFrom(sample).Len())
+	assert.Equal(t, original, s)
+
+	// Reproducible using the same seed
+	again := collection.SampleSlice(s, 4, rand.New(rand.NewPCG(1, 2)))
+	assert.Equal(t, sample, again)
+
+	all := collection.SampleSlice(s, 42, nil)
+	slices.Sort(all)
+	assert.Equal(t, original, all)
+
+	assert.Empty(t, collection.SampleSlice(s, -1, nil))
+	assert.Empty(t, collection.SampleSlice([]int{}, 3, nil))
+}
+
+func TestSampleSliceIsUniform(t *testing.T) {
+	const n = 10
+	const runs = 20000
+
+	rnd := rand.New(rand.NewPCG(3, 4))
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+
+	counts := make([]int, n)
+	for run := 0; run < runs; run++ {
+		for _, item := range collection.SampleSlice(s, 3, rnd) {
+			counts[item]++
+		}
+	}
+
+	expected := float64(runs*3) / n
+	for i, c := range counts {
+		assert.InEpsilon(t, expected, float64(c), 0.05, "item %d", i)
+	}
+}
+
+func TestSampleSeq(t *testing.T) {
+	seq := func(yield func(int) bool) {
+		for i := 0; i < 100; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	sample := collection.SampleSeq(seq, 10, rand.New(rand.NewPCG(5, 6)))
+	assert.Len(t, sample, 10)
+	assert.Equal(t, 10, collection.NewSetFrom(sample).Len())
+
+	// k much larger than the stream must not allocate k items up front
+	sample = collection.SampleSeq(seq, 1<<50, nil)
+	assert.Len(t, sample, 100)
+}
+
+func TestWeightedSample(t *testing.T) {
+	type item struct {
+		name   string
+		weight float64
+	}
+	items := []item{{"heavy", 1000}, {"light", 1}, {"zero", 0}, {"negative", -5}, {"medium", 10}}
+	weight := func(i item) float64 { return i.weight }
+
+	rnd := rand.New(rand.NewPCG(7, 8))
+	heavyFirst := 0
+	for run := 0; run < 1000; run++ {
+		sample := collection.WeightedSample(items, 2, weight, rnd)
+		assert.Len(t, sample, 2)
+		for _, s := range sample {
+			assert.Positive(t, s.weight)
+		}
+		if sample[0].name == "heavy" {
+			heavyFirst++
+		}
+	}
+	assert.Greater(t, heavyFirst, 950)
+
+	// Only 3 items can be chosen
+	sample := collection.WeightedSample(items, 5, weight, nil)
+	assert.Len(t, sample, 3)
+
+	assert.Empty(t, collection.WeightedSample(items, 0, weight, nil))
+}
+
+func BenchmarkSample(b *testing.B) {
+	s := make([]int, 100000)
+	rnd := rand.New(rand.NewPCG(1, 1))
+
+	b.Run("SampleSlice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = collection.SampleSlice(s, 100, rnd)
+		}
+	})
+
+	b.Run("SampleSeq", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = collection.SampleSeq(slices.Values(s), 100, rnd)
+		}
+	})
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// See https://en.wikipedia.org/wiki/Segment_tree for the data structure.
+
+// Monoid describes how values of type T are aggregated.
+// Combine must be associative and Identity must satisfy Combine(Identity, x) == Combine(x, Identity) == x.
+type Monoid[T any] struct {
+	Combine  func(a T, b T) T
+	Identity T
+}
+
+// SumMonoid returns a [Monoid] that adds values together.
+func SumMonoid[T Number]() Monoid[T] {
+	return Monoid[T]{
+		Combine:  func(a T, b T) T { return a + b },
+		Identity: 0,
+	}
+}
+
+// MinMonoid returns a [Monoid] that keeps the smallest value.
+// maxValue must be the largest possible value of T (e.g. math.MaxInt or math.Inf(1)).
+func MinMonoid[T cmp.Ordered](maxValue T) Monoid[T] {
+	return Monoid[T]{
+		Combine:  func(a T, b T) T { return min(a, b) },
+		Identity: maxValue,
+	}
+}
+
+// MaxMonoid returns a [Monoid] that keeps the largest value.
+// minValue must be the smallest possible value of T (e.g. math.MinInt or math.Inf(-1)).
+func MaxMonoid[T cmp.Ordered](minValue T) Monoid[T] {
+	return Monoid[T]{
+		Combine:  func(a T, b T) T { return max(a, b) },
+		Identity: minValue,
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// SegmentTree answers range aggregate queries (e.g. sum, min, max) over a fixed size array
+// in O(log n) time while still allowing individual elements to be changed in O(log n) time.
+// Ranges are half-open [lo, hi) like Go slices. Indices that are out of bounds will panic.
+// For updating whole ranges at a time see [LazySegmentTree].
+type SegmentTree[T any] struct {
+	monoid Monoid[T]
+	n      int
+	tree   []T // tree[n:] are the leaves and tree[i] = Combine(tree[2i], tree[2i+1])
+}
+
+// NewSegmentTree creates a new segment tree over a copy of the values in O(n) time.
+func NewSegmentTree[T any](values []T, monoid Monoid[T]) *SegmentTree[T] {
+	n := len(values)
+	t := &SegmentTree[T]{
+		monoid: monoid,
+		n:      n,
+		tree:   make([]T, 2*n),
+	}
+	copy(t.tree[n:], values)
+	for i := n - 1; i > 0; i-- {
+		t.tree[i] = monoid.Combine(t.tree[2*i], t.tree[2*i+1])
+	}
+	return t
+}
+
+// Len returns the number of elements.
+func (t *SegmentTree[T]) Len() int {
+	return t.n
+}
+
+// Get returns the element at index i.
+func (t *SegmentTree[T]) Get(i int) T {
+	checkIndex(i, t.n)
+	return t.tree[t.n+i]
+}
+
+// Set changes the element at index i.
+func (t *SegmentTree[T]) Set(i int, value T) {
+	checkIndex(i, t.n)
+	i += t.n
+	t.tree[i] = value
+	for i > 1 {
+		i /= 2
+		t.tree[i] = t.monoid.Combine(t.tree[2*i], t.tree[2*i+1])
+	}
+}
+
+// Query returns the aggregate of the elements in the range [lo, hi).
+// The monoid's identity is returned for an empty range.
+func (t *SegmentTree[T]) Query(lo int, hi int) T {
+	checkRange(lo, hi, t.n)
+
+	// Keep separate results for the left and right side so that Combine does not need to be commutative
+	left, right := t.monoid.Identity, t.monoid.Identity
+	for lo, hi = lo+t.n, hi+t.n; lo < hi; lo, hi = lo/2, hi/2 {
+		if lo&1 == 1 {
+			left = t.monoid.Combine(left, t.tree[lo])
+			lo++
+		}
+		if hi&1 == 1 {
+			hi--
+			right = t.monoid.Combine(t.tree[hi], right)
+		}
+	}
+	return t.monoid.Combine(left, right)
+}
+
+//-----------------------------------------------------------------------------
+
+// LazySegmentTree is a [SegmentTree] that also supports applying an update of type U to every
+// element in a range in O(log n) time by delaying (lazy propagation) the update of child nodes
+// until they are needed.
+// Ranges are half-open [lo, hi) like Go slices. Indices that are out of bounds will panic.
+type LazySegmentTree[T any, U any] struct {
+	monoid  Monoid[T]
+	apply   func(agg T, update U, length int) T
+	compose func(older U, newer U) U
+	n       int
+	tree    []T
+	lazy    []U
+	pending []bool
+}
+
+// NewLazySegmentTree creates a new segment tree over a copy of the values.
+//
+// apply must return the aggregate of length elements after update has been applied to each of them,
+// e.g. for a sum with an "add x" update it returns agg + x*length.
+// compose must return a single update that has the same effect as applying older followed by newer.
+func NewLazySegmentTree[T any, U any](values []T, monoid Monoid[T],
+	apply func(agg T, update U, length int) T,
+	compose func(older U, newer U) U) *LazySegmentTree[T, U] {
+	n := len(values)
+	size := 1
+	for size < n {
+		size *= 2
+	}
+
+	t := &LazySegmentTree[T, U]{
+		monoid:  monoid,
+		apply:   apply,
+		compose: compose,
+		n:       n,
+		tree:    make([]T, 2*size),
+		lazy:    make([]U, 2*size),
+		pending: make([]bool, 2*size),
+	}
+	if n > 0 {
+		t.build(1, 0, n, values)
+	}
+	return t
+}
+
+// Len returns the number of elements.
+func (t *LazySegmentTree[T, U]) Len() int {
+	return t.n
+}
+
+// Get returns the element at index i.
+func (t *LazySegmentTree[T, U]) Get(i int) T {
+	checkIndex(i, t.n)
+	return t.query(1, 0, t.n, i, i+1)
+}
+
+// Set changes the element at index i.
+func (t *LazySegmentTree[T, U]) Set(i int, value T) {
+	checkIndex(i, t.n)
+	t.set(1, 0, t.n, i, value)
+}
+
+// Query returns the aggregate of the elements in the range [lo, hi).
+// The monoid's identity is returned for an empty range.
+func (t *LazySegmentTree[T, U]) Query(lo int, hi int) T {
+	checkRange(lo, hi, t.n)
+	if lo == hi {
+		return t.monoid.Identity
+	}
+	return t.query(1, 0, t.n, lo, hi)
+}
+
+// Update applies the update to every element in the range [lo, hi).
+func (t *LazySegmentTree[T, U]) Update(lo int, hi int, update U) {
+	checkRange(lo, hi, t.n)
+	if lo == hi {
+		return
+	}
+	t.update(1, 0, t.n, lo, hi, update)
+}
+
+// Node i covers the range [nodeLo, nodeHi).
+
+func (t *LazySegmentTree[T, U]) build(i int, nodeLo int, nodeHi int, values []T) {
+	if nodeHi-nodeLo == 1 {
+		t.tree[i] = values[nodeLo]
+		return
+	}
+	mid := nodeLo + (nodeHi-nodeLo)/2
+	t.build(2*i, nodeLo, mid, values)
+	t.build(2*i+1, mid, nodeHi, values)
+	t.tree[i] = t.monoid.Combine(t.tree[2*i], t.tree[2*i+1])
+}
+
+// applyNode applies the update to the aggregate of node i and records it for its children.
+func (t *LazySegmentTree[T, U]) applyNode(i int, length int, update U) {
+	t.tree[i] = t.apply(t.tree[i], update, length)
+	if t.pending[i] {
+		t.lazy[i] = t.compose(t.lazy[i], update)
+	} else {
+		t.lazy[i] = update
+		t.pending[i] = true
+	}
+}
+
+// push moves the pending update of node i to its children.
+func (t *LazySegmentTree[T, U]) push(i int, nodeLo int, mid int, nodeHi int) {
+	if !t.pending[i] {
+		return
+	}
+	t.applyNode(2*i, mid-nodeLo, t.lazy[i])
+	t.applyNode(2*i+1, nodeHi-mid, t.lazy[i])
+	var zero U
+	t.lazy[i] = zero
+	t.pending[i] = false
+}
+
+func (t *LazySegmentTree[T, U]) query(i int, nodeLo int, nodeHi int, lo int, hi int) T {
+	if lo <= nodeLo && nodeHi <= hi {
+		return t.tree[i]
+	}
+	mid := nodeLo + (nodeHi-nodeLo)/2
+	t.push(i, nodeLo, mid, nodeHi)
+
+	switch {
+	case hi <= mid:
+		return t.query(2*i, nodeLo, mid, lo, hi)
+	case lo >= mid:
+		return t.query(2*i+1, mid, nodeHi, lo, hi)
+	}
+	return t.monoid.Combine(t.query(2*i, nodeLo, mid, lo, hi), t.query(2*i+1, mid, nodeHi, lo, hi))
+}
+
+func (t *LazySegmentTree[T, U]) update(i int, nodeLo int, nodeHi int, lo int, hi int, update U) {
+	if hi <= nodeLo || nodeHi <= lo {
+		return
+	}
+	if lo <= nodeLo && nodeHi <= hi {
+		t.applyNode(i, nodeHi-nodeLo, update)
+		return
+	}
+	mid := nodeLo + (nodeHi-nodeLo)/2
+	t.push(i, nodeLo, mid, nodeHi)
+	t.update(2*i, nodeLo, mid, lo, hi, update)
+	t.update(2*i+1, mid, nodeHi, lo, hi, update)
+	t.tree[i] = t.monoid.Combine(t.tree[2*i], t.tree[2*i+1])
+}
+
+func (t *LazySegmentTree[T, U]) set(i int, nodeLo int, nodeHi int, index int, value T) {
+	if nodeHi-nodeLo == 1 {
+		t.tree[i] = value
+		return
+	}
+	mid := nodeLo + (nodeHi-nodeLo)/2
+	t.push(i, nodeLo, mid, nodeHi)
+	if index < mid {
+		t.set(2*i, nodeLo, mid, index, value)
+	} else {
+		t.set(2*i+1, mid, nodeHi, index, value)
+	}
+	t.tree[i] = t.monoid.Combine(t.tree[2*i], t.tree[2*i+1])
+}
+
+//-----------------------------------------------------------------------------
+
+func checkIndex(i int, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("index %d out of range [0, %d)", i, n))
+	}
+}
+
+func checkRange(lo int, hi int, n int) {
+	if lo < 0 || hi > n || lo > hi {
+		panic(fmt.Sprintf("invalid range [%d, %d) for length %d", lo, hi, n))
+	}
+}
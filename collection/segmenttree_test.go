@@ -0,0 +1,186 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentTree(t *testing.T) {
+	values := []int{5, 3, 8, 1, 9, 2, 7}
+	sum := collection.NewSegmentTree(values, collection.SumMonoid[int]())
+	minTree := collection.NewSegmentTree(values, collection.MinMonoid(math.MaxInt))
+	maxTree := collection.NewSegmentTree(values, collection.MaxMonoid(math.MinInt))
+
+	assert.Equal(t, 7, sum.Len())
+	assert.Equal(t, 35, sum.Query(0, 7))
+	assert.Equal(t, 12, sum.Query(1, 4))
+	assert.Equal(t, 0, sum.Query(3, 3))
+	assert.Equal(t, 1, minTree.Query(0, 7))
+	assert.Equal(t, 3, minTree.Query(0, 3))
+	assert.Equal(t, math.MaxInt, minTree.Query(2, 2))
+	assert.Equal(t, 9, maxTree.Query(2, 6))
+
+	sum.Set(3, 100)
+	assert.Equal(t, 100, sum.Get(3))
+	assert.Equal(t, 111, sum.Query(1, 4))
+
+	// The input is copied
+	values[0] = 1000
+	assert.Equal(t, 5, sum.Get(0))
+
+	assert.Panics(t, func() { sum.Get(7) })
+	assert.Panics(t, func() { sum.Query(4, 2) })
+	assert.Panics(t, func() { sum.Query(0, 8) })
+}
+
+func TestSegmentTreeNonCommutative(t *testing.T) {
+	concat := collection.Monoid[string]{
+		Combine:  func(a, b string) string { return a + b },
+		Identity: "",
+	}
+	tr := collection.NewSegmentTree([]string{"a", "b", "c", "d", "e"}, concat)
+	assert.Equal(t, "abcde", tr.Query(0, 5))
+	assert.Equal(t, "bcd", tr.Query(1, 4))
+	tr.Set(2, "X")
+	assert.Equal(t, "bXd", tr.Query(1, 4))
+}
+
+func TestSegmentTreeEmpty(t *testing.T) {
+	tr := collection.NewSegmentTree([]int{}, collection.SumMonoid[int]())
+	assert.Equal(t, 0, tr.Len())
+	assert.Equal(t, 0, tr.Query(0, 0))
+
+	lazy := collection.NewLazySegmentTree([]int{}, collection.SumMonoid[int](), addToSum, addCompose)
+	assert.Equal(t, 0, lazy.Query(0, 0))
+}
+
+func addToSum(agg int, update int, length int) int { return agg + update*length }
+func addToMin(agg int, update int, _ int) int      { return agg + update }
+func addCompose(older int, newer int) int          { return older + newer }
+
+func TestLazySegmentTree(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	sum := collection.NewLazySegmentTree(values, collection.SumMonoid[int](), addToSum, addCompose)
+	minTree := collection.NewLazySegmentTree(values, collection.MinMonoid(math.MaxInt), addToMin, addCompose)
+
+	assert.Equal(t, 55, sum.Query(0, 10))
+	sum.Update(2, 5, 10)
+	assert.Equal(t, 85, sum.Query(0, 10))
+	assert.Equal(t, 13, sum.Get(2))
+	assert.Equal(t, 2, sum.Get(1))
+	assert.Equal(t, 29, sum.Query(3, 5))
+
+	sum.Set(3, 0)
+	assert.Equal(t, 71, sum.Query(0, 10))
+
+	minTree.Update(0, 5, 100)
+	assert.Equal(t, 6, minTree.Query(0, 10))
+	minTree.Update(5, 10, -10)
+	assert.Equal(t, -4, minTree.Query(0, 10))
+	assert.Equal(t, 101, minTree.Query(0, 5))
+}
+
+// Assign a value to a range while keeping track of the sum.
+func TestLazySegmentTreeAssign(t *testing.T) {
+	assign := func(_ int, update int, length int) int { return update * length }
+	latest := func(_ int, newer int) int { return newer }
+	tr := collection.NewLazySegmentTree(make([]int, 8), collection.SumMonoid[int](), assign, latest)
+
+	tr.Update(0, 8, 1)
+	tr.Update(2, 6, 5)
+	tr.Update(4, 5, 0)
+	assert.Equal(t, []int{1, 1, 5, 5, 0, 5, 1, 1}, []int{
+		tr.Get(0), tr.Get(1), tr.Get(2), tr.Get(3), tr.Get(4), tr.Get(5), tr.Get(6), tr.Get(7),
+	})
+	assert.Equal(t, 19, tr.Query(0, 8))
+}
+
+func TestLazySegmentTreeBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(5, 6))
+	const n = 37
+	values := make([]int, n)
+	for i := range values {
+		values[i] = rnd.IntN(100)
+	}
+	tr := collection.NewLazySegmentTree(values, collection.SumMonoid[int](), addToSum, addCompose)
+
+	for op := 0; op < 2000; op++ {
+		lo := rnd.IntN(n + 1)
+		hi := lo + rnd.IntN(n-lo+1)
+		switch rnd.IntN(3) {
+		case 0:
+			delta := rnd.IntN(21) - 10
+			tr.Update(lo, hi, delta)
+			for i := lo; i < hi; i++ {
+				values[i] += delta
+			}
+		case 1:
+			if lo < n {
+				v := rnd.IntN(100)
+				tr.Set(lo, v)
+				values[lo] = v
+			}
+		default:
+			expected := 0
+			for i := lo; i < hi; i++ {
+				expected += values[i]
+			}
+			require.Equal(t, expected, tr.Query(lo, hi), "query [%d, %d)", lo, hi)
+		}
+	}
+}
+
+func BenchmarkSegmentTree(b *testing.B) {
+	values := make([]int, 100000)
+	for i := range values {
+		values[i] = i
+	}
+	tr := collection.NewSegmentTree(values, collection.SumMonoid[int]())
+	lazy := collection.NewLazySegmentTree(values, collection.SumMonoid[int](), addToSum, addCompose)
+
+	b.Run("Query", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = tr.Query(i%50000, 50000+i%50000)
+		}
+	})
+
+	b.Run("LazyUpdate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			lazy.Update(i%50000, 50000+i%50000, 1)
+		}
+	})
+
+	b.Run("SliceScan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for _, v := range values[i%50000 : 50000+i%50000] {
+				sum += v
+			}
+			_ = sum
+		}
+	})
+}
@@ -19,6 +19,11 @@
 
 package collection
 
+import (
+	"iter"
+	"maps"
+)
+
 // Set contains a collection of unique items.
 // See https://en.wikipedia.org/wiki/Set_(mathematics) for set theory.
 type Set[T comparable] struct {
@@ -119,6 +124,25 @@ func (s Set[T]) ContainsSlice(items []T) bool {
 	return true
 }
 
+// All returns an iterator over all the items in the set.
+func (s Set[T]) All() iter.Seq[T] {
+	return maps.Keys(s.items)
+}
+
+// Return a new set that contains the same items.
+// Changes made to the new set will not affect this set.
+func (s Set[T]) Clone() Set[T] {
+	return Set[T]{
+		items: maps.Clone(s.items),
+	}
+}
+
+// AsReadOnly returns a read-only view of the set.
+// The view reflects changes made to the set but can't be used to modify it.
+func (s Set[T]) AsReadOnly() ReadOnlySet[T] {
+	return readOnlySet[T]{s: s}
+}
+
 // Return a new set that is the union of this set and another.
 func (a Set[T]) Union(b Set[T]) Set[T] {
 	c := Set[T]{
@@ -20,6 +20,7 @@
 package collection_test
 
 import (
+	"slices"
 	"sort"
 	"testing"
 
@@ -182,3 +183,18 @@ func BenchmarkSet(b *testing.B) {
 		}
 	})
 }
+
+func TestSetAllAndClone(t *testing.T) {
+	a := collection.NewSetFrom([]int{1, 3, 5})
+
+	items := slices.Collect(a.All())
+	sort.Ints(items)
+	assert.Equal(t, []int{1, 3, 5}, items)
+
+	b := a.Clone()
+	b.Insert(7)
+	a.Remove(1)
+	assert.Equal(t, 2, a.Len())
+	assert.Equal(t, 4, b.Len())
+	assert.True(t, b.Contains(1))
+}
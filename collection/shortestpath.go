@@ -0,0 +1,338 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// See https://en.wikipedia.org/wiki/Shortest_path_problem for the algorithms used.
+
+// ErrNegativeWeight is returned by algorithms that do not support negative edge weights.
+var ErrNegativeWeight = errors.New("graph contains a negative edge weight")
+
+// NegativeCycleError is returned when a shortest path can not be determined because the graph
+// contains a cycle whose total weight is negative.
+type NegativeCycleError[N comparable] struct {
+	// Cycle lists the nodes that make up the cycle where the first node is repeated at the end,
+	// e.g. [a b c a].
+	Cycle []N
+}
+
+func (e *NegativeCycleError[N]) Error() string {
+	parts := make([]string, 0, len(e.Cycle))
+	for _, n := range e.Cycle {
+		parts = append(parts, fmt.Sprint(n))
+	}
+	return fmt.Sprintf("graph contains a negative cycle: %s", strings.Join(parts, " -> "))
+}
+
+// ShortestPaths contains the shortest paths from a single source node to every reachable node.
+type ShortestPaths[N comparable, W Number] struct {
+	// Source is the node from which the paths start.
+	Source N
+	// Distances contains the total weight of the shortest path to each reachable node.
+	// Nodes that can not be reached are not in the map.
+	Distances map[N]W
+	previous  map[N]N
+}
+
+// PathTo returns the nodes along the shortest path from the source to the target (inclusive).
+// Returns nil if the target can not be reached.
+func (p *ShortestPaths[N, W]) PathTo(target N) []N {
+	if _, reachable := p.Distances[target]; !reachable {
+		return nil
+	}
+
+	path := []N{target}
+	for target != p.Source {
+		target = p.previous[target]
+		path = append(path, target)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Dijkstra calculates the shortest paths from the source to every reachable node in O((V + E) log V) time.
+// [ErrNegativeWeight] is returned if a negative edge weight is encountered, use [WeightedGraph.BellmanFord] instead.
+func (g *WeightedGraph[N, W]) Dijkstra(source N) (*ShortestPaths[N, W], error) {
+	type entry struct {
+		node N
+		dist W
+	}
+
+	if !g.HasNode(source) {
+		return nil, fmt.Errorf("source node %v is not in the graph", source)
+	}
+
+	result := newShortestPaths[N, W](source)
+	done := NewSet[N]()
+	pq := NewPriorityQueue(func(a entry, b entry) bool {
+		return a.dist < b.dist
+	})
+	pq.Push(entry{node: source})
+
+	for pq.Len() > 0 {
+		current, _ := pq.Pop()
+		if !done.Insert(current.node) {
+			// Stale entry for a node that was already reached via a shorter path
+			continue
+		}
+
+		for _, to := range g.Successors(current.node) {
+			w, _ := g.Weight(current.node, to)
+			if w < 0 {
+				return nil, ErrNegativeWeight
+			}
+			dist := current.dist + w
+			if existing, reached := result.Distances[to]; !reached || dist < existing {
+				result.Distances[to] = dist
+				result.previous[to] = current.node
+				pq.Push(entry{node: to, dist: dist})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// BellmanFord calculates the shortest paths from the source to every reachable node in O(V * E) time.
+// Unlike [WeightedGraph.Dijkstra], negative edge weights are supported.
+// A [*NegativeCycleError] is returned if a negative cycle can be reached from the source.
+func (g *WeightedGraph[N, W]) BellmanFord(source N) (*ShortestPaths[N, W], error) {
+	if !g.HasNode(source) {
+		return nil, fmt.Errorf("source node %v is not in the graph", source)
+	}
+
+	result := newShortestPaths[N, W](source)
+	edges := g.Edges()
+
+	relax := func() (WeightedEdge[N, W], bool) {
+		relaxed := false
+		var last WeightedEdge[N, W]
+		for _, e := range edges {
+			from, reached := result.Distances[e.From]
+			if !reached {
+				continue
+			}
+			dist := from + e.Weight
+			if existing, reached := result.Distances[e.To]; !reached || dist < existing {
+				result.Distances[e.To] = dist
+				result.previous[e.To] = e.From
+				relaxed = true
+				last = e
+			}
+		}
+		return last, relaxed
+	}
+
+	for i := 0; i < g.Len()-1; i++ {
+		if _, relaxed := relax(); !relaxed {
+			return result, nil
+		}
+	}
+
+	e, relaxed := relax()
+	if !relaxed {
+		return result, nil
+	}
+
+	// Walk back far enough to be sure to end up inside the cycle
+	n := e.To
+	for i := 0; i < g.Len(); i++ {
+		n = result.previous[n]
+	}
+	cycle := []N{n}
+	for p := result.previous[n]; p != n; p = result.previous[p] {
+		cycle = append(cycle, p)
+	}
+	cycle = append(cycle, n)
+	slices.Reverse(cycle)
+
+	return nil, &NegativeCycleError[N]{Cycle: cycle}
+}
+
+// AStar finds the shortest path from the source to the target using the heuristic to guide the search.
+// The heuristic must return an estimate of the remaining distance from a node to the target that never
+// overestimates the actual distance, otherwise the path returned might not be the shortest.
+// The heuristic does not need to be consistent, nodes are expanded again when a shorter path to them is found.
+// Returns the path (inclusive of source and target) and the total weight, the path is nil if the target
+// can not be reached. [ErrNegativeWeight] is returned if a negative edge weight is encountered.
+func (g *WeightedGraph[N, W]) AStar(source N, target N, heuristic func(node N) W) ([]N, W, error) {
+	type entry struct {
+		node     N
+		dist     W
+		estimate W
+	}
+
+	var zero W
+	if !g.HasNode(source) {
+		return nil, zero, fmt.Errorf("source node %v is not in the graph", source)
+	}
+	if !g.HasNode(target) {
+		return nil, zero, fmt.Errorf("target node %v is not in the graph", target)
+	}
+
+	paths := newShortestPaths[N, W](source)
+	pq := NewPriorityQueue(func(a entry, b entry) bool {
+		return a.estimate < b.estimate
+	})
+	pq.Push(entry{node: source, estimate: heuristic(source)})
+
+	for pq.Len() > 0 {
+		current, _ := pq.Pop()
+		if current.dist > paths.Distances[current.node] {
+			// Stale entry for a node that has since been reached via a shorter path
+			continue
+		}
+		if current.node == target {
+			return paths.PathTo(target), current.dist, nil
+		}
+
+		for _, to := range g.Successors(current.node) {
+			w, _ := g.Weight(current.node, to)
+			if w < 0 {
+				return nil, zero, ErrNegativeWeight
+			}
+			dist := current.dist + w
+			if existing, reached := paths.Distances[to]; !reached || dist < existing {
+				paths.Distances[to] = dist
+				paths.previous[to] = current.node
+				pq.Push(entry{node: to, dist: dist, estimate: dist + heuristic(to)})
+			}
+		}
+	}
+
+	return nil, zero, nil
+}
+
+// AllShortestPaths contains the shortest paths between every pair of nodes.
+type AllShortestPaths[N comparable, W Number] struct {
+	// Distances[from][to] contains the total weight of the shortest path.
+	// Pairs of nodes that have no path between them are not in the map.
+	Distances map[N]map[N]W
+	next      map[N]map[N]N
+}
+
+// Distance returns the total weight of the shortest path from -> to and true if a path exists.
+func (p *AllShortestPaths[N, W]) Distance(from N, to N) (W, bool) {
+	dist, exists := p.Distances[from][to]
+	return dist, exists
+}
+
+// Path returns the nodes along the shortest path from -> to (inclusive).
+// Returns nil if there is no path.
+func (p *AllShortestPaths[N, W]) Path(from N, to N) []N {
+	if _, exists := p.Distances[from][to]; !exists {
+		return nil
+	}
+
+	path := []N{from}
+	for from != to {
+		from = p.next[from][to]
+		path = append(path, from)
+	}
+	return path
+}
+
+// FloydWarshall calculates the shortest paths between every pair of nodes in O(V^3) time and O(V^2) memory,
+// which makes it only suitable for small graphs. Negative edge weights are supported.
+// A [*NegativeCycleError] is returned if the graph contains a negative cycle.
+func (g *WeightedGraph[N, W]) FloydWarshall() (*AllShortestPaths[N, W], error) {
+	nodes := g.Nodes()
+	n := len(nodes)
+	index := make(map[N]int, n)
+	for i, node := range nodes {
+		index[node] = i
+	}
+
+	dist := make([][]W, n)
+	reached := make([][]bool, n)
+	next := make([][]int, n)
+	for i := range nodes {
+		dist[i] = make([]W, n)
+		reached[i] = make([]bool, n)
+		next[i] = make([]int, n)
+		reached[i][i] = true
+		next[i][i] = i
+	}
+	for _, e := range g.Edges() {
+		i, j := index[e.From], index[e.To]
+		if !reached[i][j] || e.Weight < dist[i][j] {
+			dist[i][j] = e.Weight
+			reached[i][j] = true
+			next[i][j] = j
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if !reached[i][k] {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if !reached[k][j] {
+					continue
+				}
+				if d := dist[i][k] + dist[k][j]; !reached[i][j] || d < dist[i][j] {
+					dist[i][j] = d
+					reached[i][j] = true
+					next[i][j] = next[i][k]
+				}
+			}
+		}
+	}
+
+	for i := range nodes {
+		if dist[i][i] < 0 {
+			// Use Bellman-Ford to extract the cycle
+			_, err := g.BellmanFord(nodes[i])
+			return nil, err
+		}
+	}
+
+	result := &AllShortestPaths[N, W]{
+		Distances: make(map[N]map[N]W, n),
+		next:      make(map[N]map[N]N, n),
+	}
+	for i, from := range nodes {
+		result.Distances[from] = make(map[N]W)
+		result.next[from] = make(map[N]N)
+		for j, to := range nodes {
+			if reached[i][j] {
+				result.Distances[from][to] = dist[i][j]
+				result.next[from][to] = nodes[next[i][j]]
+			}
+		}
+	}
+	return result, nil
+}
+
+func newShortestPaths[N comparable, W Number](source N) *ShortestPaths[N, W] {
+	var zero W
+	return &ShortestPaths[N, W]{
+		Source:    source,
+		Distances: map[N]W{source: zero},
+		previous:  make(map[N]N),
+	}
+}
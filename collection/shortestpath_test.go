@@ -0,0 +1,288 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exampleRoadGraph() *collection.WeightedGraph[string, int] {
+	g := collection.NewWeightedGraph[string, int]()
+	g.AddUndirectedEdge("a", "b", 7)
+	g.AddUndirectedEdge("a", "c", 9)
+	g.AddUndirectedEdge("a", "f", 14)
+	g.AddUndirectedEdge("b", "c", 10)
+	g.AddUndirectedEdge("b", "d", 15)
+	g.AddUndirectedEdge("c", "d", 11)
+	g.AddUndirectedEdge("c", "f", 2)
+	g.AddUndirectedEdge("d", "e", 6)
+	g.AddUndirectedEdge("e", "f", 9)
+	g.AddNode("island")
+	return g
+}
+
+func TestDijkstra(t *testing.T) {
+	g := exampleRoadGraph()
+	paths, err := g.Dijkstra("a")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"a": 0, "b": 7, "c": 9, "d": 20, "e": 20, "f": 11}, paths.Distances)
+	assert.Equal(t, []string{"a", "c", "f", "e"}, paths.PathTo("e"))
+	assert.Equal(t, []string{"a"}, paths.PathTo("a"))
+	assert.Nil(t, paths.PathTo("island"))
+
+	g.AddEdge("e", "island", -1)
+	_, err = g.Dijkstra("a")
+	assert.ErrorIs(t, err, collection.ErrNegativeWeight)
+}
+
+func TestBellmanFord(t *testing.T) {
+	g := collection.NewWeightedGraph[string, int]()
+	g.AddEdge("s", "a", 4)
+	g.AddEdge("s", "b", 5)
+	g.AddEdge("a", "c", -3)
+	g.AddEdge("b", "a", -2)
+	g.AddEdge("c", "d", 2)
+
+	paths, err := g.BellmanFord("s")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"s": 0, "a": 3, "b": 5, "c": 0, "d": 2}, paths.Distances)
+	assert.Equal(t, []string{"s", "b", "a", "c", "d"}, paths.PathTo("d"))
+
+	g.AddEdge("d", "b", -5)
+	_, err = g.BellmanFord("s")
+	var cycleErr *collection.NegativeCycleError[string]
+	require.True(t, errors.As(err, &cycleErr))
+	require.Len(t, cycleErr.Cycle, 5)
+	assert.Equal(t, cycleErr.Cycle[0], cycleErr.Cycle[4])
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, cycleErr.Cycle[:4])
+	assert.Contains(t, err.Error(), "graph contains a negative cycle: ")
+}
+
+func TestAStar(t *testing.T) {
+	type point struct{ x, y int }
+	abs := func(v int) int {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+
+	// 5x5 grid with a wall in column 2 except for the bottom row
+	g := collection.NewWeightedGraph[point, int]()
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			if x == 2 && y < 4 {
+				continue
+			}
+			if x+1 < 5 && !(x+1 == 2 && y < 4) {
+				g.AddUndirectedEdge(point{x, y}, point{x + 1, y}, 1)
+			}
+			if y+1 < 5 && !(x == 2 && y+1 < 4) {
+				g.AddUndirectedEdge(point{x, y}, point{x, y + 1}, 1)
+			}
+		}
+	}
+
+	target := point{4, 0}
+	manhattan := func(p point) int { return abs(p.x-target.x) + abs(p.y-target.y) }
+	path, dist, err := g.AStar(point{0, 0}, target, manhattan)
+	require.NoError(t, err)
+	assert.Equal(t, 12, dist)
+	assert.Len(t, path, 13)
+	assert.Equal(t, point{0, 0}, path[0])
+	assert.Equal(t, target, path[12])
+
+	g.AddNode(point{42, 42})
+	path, _, err = g.AStar(point{0, 0}, point{42, 42}, func(point) int { return 0 })
+	require.NoError(t, err)
+	assert.Nil(t, path)
+
+	_, _, err = g.AStar(point{0, 0}, point{99, 99}, manhattan)
+	assert.EqualError(t, err, "target node {99 99} is not in the graph")
+	_, _, err = g.AStar(point{99, 99}, target, manhattan)
+	assert.EqualError(t, err, "source node {99 99} is not in the graph")
+}
+
+func TestAStarInconsistentHeuristic(t *testing.T) {
+	g := collection.NewWeightedGraph[string, int]()
+	g.AddEdge("s", "a", 1)
+	g.AddEdge("s", "b", 3)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "t", 5)
+
+	// Admissible but not consistent: h(a) overestimates the cost of the edge a -> b
+	h := func(n string) int {
+		if n == "a" {
+			return 6
+		}
+		return 0
+	}
+	path, dist, err := g.AStar("s", "t", h)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s", "a", "b", "t"}, path)
+	assert.Equal(t, 7, dist)
+	assert.Equal(t, dist, pathWeight(t, g, path))
+}
+
+func TestAStarNegativeWeight(t *testing.T) {
+	g := collection.NewWeightedGraph[string, int]()
+	g.AddEdge("s", "a", 2)
+	g.AddEdge("a", "t", -1)
+
+	_, _, err := g.AStar("s", "t", func(string) int { return 0 })
+	assert.ErrorIs(t, err, collection.ErrNegativeWeight)
+}
+
+func TestShortestPathUnknownSource(t *testing.T) {
+	g := exampleRoadGraph()
+	_, err := g.Dijkstra("z")
+	assert.EqualError(t, err, "source node z is not in the graph")
+	_, err = g.BellmanFord("z")
+	assert.EqualError(t, err, "source node z is not in the graph")
+}
+
+func TestFloydWarshall(t *testing.T) {
+	g := exampleRoadGraph()
+	all, err := g.FloydWarshall()
+	require.NoError(t, err)
+
+	dist, ok := all.Distance("a", "e")
+	assert.True(t, ok)
+	assert.Equal(t, 20, dist)
+	assert.Equal(t, []string{"a", "c", "f", "e"}, all.Path("a", "e"))
+	assert.Equal(t, []string{"e", "f", "c", "a"}, all.Path("e", "a"))
+	assert.Equal(t, []string{"d"}, all.Path("d", "d"))
+	_, ok = all.Distance("a", "island")
+	assert.False(t, ok)
+	assert.Nil(t, all.Path("a", "island"))
+
+	g.AddEdge("island", "a", 1)
+	g.AddEdge("a", "island", -2)
+	_, err = g.FloydWarshall()
+	assert.True(t, errors.As(err, new(*collection.NegativeCycleError[string])))
+}
+
+// bruteForceShortest enumerates every simple path from source.
+func bruteForceShortest(g *collection.WeightedGraph[int, int], source int) map[int]int {
+	best := map[int]int{}
+	visited := collection.NewSet[int]()
+
+	var visit func(n int, dist int)
+	visit = func(n int, dist int) {
+		if existing, ok := best[n]; !ok || dist < existing {
+			best[n] = dist
+		}
+		visited.Insert(n)
+		for _, to := range g.Successors(n) {
+			if !visited.Contains(to) {
+				w, _ := g.Weight(n, to)
+				visit(to, dist+w)
+			}
+		}
+		visited.Remove(n)
+	}
+	visit(source, 0)
+	return best
+}
+
+func pathWeight[N comparable](t *testing.T, g *collection.WeightedGraph[N, int], path []N) int {
+	total := 0
+	for i := 1; i < len(path); i++ {
+		w, ok := g.Weight(path[i-1], path[i])
+		require.True(t, ok)
+		total += w
+	}
+	return total
+}
+
+func TestShortestPathsAgainstBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(11, 12))
+
+	for run := 0; run < 50; run++ {
+		const n = 7
+		g := collection.NewWeightedGraph[int, int]()
+		dag := collection.NewWeightedGraph[int, int]()
+		for i := 0; i < n; i++ {
+			g.AddNode(i)
+			dag.AddNode(i)
+		}
+		for e := 0; e < 15; e++ {
+			a, b := rnd.IntN(n), rnd.IntN(n)
+			if a != b {
+				g.AddEdge(a, b, rnd.IntN(20))
+			}
+			if a < b {
+				// Negative weights without any cycles
+				dag.AddEdge(a, b, rnd.IntN(20)-10)
+			}
+		}
+
+		all, err := g.FloydWarshall()
+		require.NoError(t, err)
+		dagAll, err := dag.FloydWarshall()
+		require.NoError(t, err)
+
+		for source := 0; source < n; source++ {
+			expected := bruteForceShortest(g, source)
+
+			dijkstra, err := g.Dijkstra(source)
+			require.NoError(t, err)
+			assert.Equal(t, expected, dijkstra.Distances)
+
+			bellman, err := g.BellmanFord(source)
+			require.NoError(t, err)
+			assert.Equal(t, expected, bellman.Distances)
+
+			for target := 0; target < n; target++ {
+				dist, ok := all.Distance(source, target)
+				expectedDist, expectedOk := expected[target]
+				assert.Equal(t, expectedOk, ok)
+				assert.Equal(t, expectedDist, dist)
+
+				path, astarDist, err := g.AStar(source, target, func(int) int { return 0 })
+				require.NoError(t, err)
+				assert.Equal(t, expectedOk, path != nil)
+				if expectedOk {
+					assert.Equal(t, expectedDist, astarDist)
+					assert.Equal(t, expectedDist, pathWeight(t, g, path))
+					assert.Equal(t, expectedDist, pathWeight(t, g, dijkstra.PathTo(target)))
+					assert.Equal(t, expectedDist, pathWeight(t, g, all.Path(source, target)))
+				}
+			}
+
+			dagExpected := bruteForceShortest(dag, source)
+			dagBellman, err := dag.BellmanFord(source)
+			require.NoError(t, err)
+			assert.Equal(t, dagExpected, dagBellman.Distances)
+			for target, d := range dagExpected {
+				dist, _ := dagAll.Distance(source, target)
+				assert.Equal(t, d, dist)
+				assert.Equal(t, d, pathWeight(t, dag, dagBellman.PathTo(target)))
+			}
+		}
+	}
+}
@@ -19,7 +19,11 @@
 
 package collection
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
 
 //-----------------------------------------------------------------------------
 // Commonly used sliced tricks
@@ -60,6 +64,364 @@ func SliceSafeRemoveAtFast[T any](s []T, index int) ([]T, error) {
 	return SliceRemoveAtFast(s, index), nil
 }
 
+//-----------------------------------------------------------------------------
+// Bulk removal
+// These functions make a single pass over the slice and modify the underlying array in place.
+// The elements past the new length are zeroed so that they can be garbage collected.
+// The Fast variants don't preserve the element order and instead fill the gaps with elements
+// from the end of the slice, like SliceRemoveAtFast, which moves fewer elements.
+
+// Remove all the elements for which the predicate returns true while preserving the element order.
+// NOTE: The underlying array is modified.
+func SliceRemoveIf[T any](s []T, predicate func(item T) bool) []T {
+	return slices.DeleteFunc(s, predicate)
+}
+
+// Remove all the elements for which the predicate returns true without preserving the element order.
+// NOTE: The underlying array is modified.
+func SliceRemoveIfFast[T any](s []T, predicate func(item T) bool) []T {
+	n := len(s)
+	for i := 0; i < n; {
+		if predicate(s[i]) {
+			n--
+			s[i] = s[n]
+		} else {
+			i++
+		}
+	}
+	clear(s[n:])
+	return s[:n]
+}
+
+// Only keep the elements for which the predicate returns true while preserving the element order.
+// NOTE: The underlying array is modified.
+func SliceRetainIf[T any](s []T, predicate func(item T) bool) []T {
+	return SliceFilterInPlace(s, predicate)
+}
+
+// Only keep the elements for which the predicate returns true without preserving the element order.
+// NOTE: The underlying array is modified.
+func SliceRetainIfFast[T any](s []T, predicate func(item T) bool) []T {
+	return SliceRemoveIfFast(s, func(item T) bool { return !predicate(item) })
+}
+
+// Remove the elements at the specified indices while preserving the element order.
+// The indices may be in any order and may contain duplicates. The indices refer to the positions in
+// the original slice, so there is no need to account for elements shifting as they are removed.
+// NOTE: The underlying array is modified.
+// Panics if any of the indices are out of bounds.
+func SliceRemoveIndices[T any](s []T, indices ...int) []T {
+	remove := sortedUniqueIndices(indices, len(s))
+	if len(remove) == 0 {
+		return s
+	}
+
+	w := remove[0]
+	next := 0
+	for r := remove[0]; r < len(s); r++ {
+		if next < len(remove) && remove[next] == r {
+			next++
+			continue
+		}
+		s[w] = s[r]
+		w++
+	}
+	clear(s[w:])
+	return s[:w]
+}
+
+// Remove the elements at the specified indices without preserving the element order.
+// The indices may be in any order and may contain duplicates. The indices refer to the positions in
+// the original slice.
+// NOTE: The underlying array is modified.
+// Panics if any of the indices are out of bounds.
+func SliceRemoveIndicesFast[T any](s []T, indices ...int) []T {
+	remove := sortedUniqueIndices(indices, len(s))
+
+	// Removing from the highest index first means the last element is never one that still needs to be removed
+	n := len(s)
+	for i := len(remove) - 1; i >= 0; i-- {
+		n--
+		s[remove[i]] = s[n]
+	}
+	clear(s[n:])
+	return s[:n]
+}
+
+// Remove the elements in the range [lo, hi) while preserving the element order.
+// NOTE: The underlying array is modified.
+// Panics if the range is out of bounds or lo > hi.
+func SliceRemoveRange[T any](s []T, lo int, hi int) []T {
+	checkRange(lo, hi, len(s))
+	return slices.Delete(s, lo, hi)
+}
+
+// Remove the elements in the range [lo, hi) without preserving the element order.
+// The gap is filled with the elements from the end of the slice.
+// NOTE: The underlying array is modified.
+// Panics if the range is out of bounds or lo > hi.
+func SliceRemoveRangeFast[T any](s []T, lo int, hi int) []T {
+	checkRange(lo, hi, len(s))
+	n := len(s)
+	removed := hi - lo
+	moved := min(removed, n-hi)
+	copy(s[lo:lo+moved], s[n-moved:])
+	clear(s[n-removed:])
+	return s[:n-removed]
+}
+
+// sortedUniqueIndices returns a sorted copy of the indices without duplicates.
+// Panics if any of the indices are not within [0, n).
+func sortedUniqueIndices(indices []int, n int) []int {
+	result := slices.Clone(indices)
+	slices.Sort(result)
+	result = slices.Compact(result)
+	if len(result) > 0 {
+		checkIndex(result[0], n)
+		checkIndex(result[len(result)-1], n)
+	}
+	return result
+}
+
+//-----------------------------------------------------------------------------
+// Functional transforms
+
+// Return a new slice with fn applied to every element.
+func SliceMap[T any, U any](s []T, fn func(item T) U) []U {
+	result := make([]U, len(s))
+	for i, item := range s {
+		result[i] = fn(item)
+	}
+	return result
+}
+
+// Return a new slice that only contains the elements for which the predicate returns true.
+// The element order is preserved and s is not modified.
+func SliceFilter[T any](s []T, predicate func(item T) bool) []T {
+	result := make([]T, 0, len(s))
+	for _, item := range s {
+		if predicate(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Only keep the elements for which the predicate returns true while preserving the element order.
+// NOTE: The underlying array is modified and the elements past the new length are zeroed so that
+// they can be garbage collected.
+func SliceFilterInPlace[T any](s []T, predicate func(item T) bool) []T {
+	n := 0
+	for _, item := range s {
+		if predicate(item) {
+			s[n] = item
+			n++
+		}
+	}
+	clear(s[n:])
+	return s[:n]
+}
+
+// Combine all the elements into a single value by calling fn with the result so far and the next
+// element, starting with initial.
+func SliceReduce[T any, U any](s []T, initial U, fn func(result U, item T) U) U {
+	result := initial
+	for _, item := range s {
+		result = fn(result, item)
+	}
+	return result
+}
+
+// Return a new map where the elements are grouped by the key function.
+// The elements in each group are in the same order as in s.
+func SliceGroupBy[T any, K comparable](s []T, key func(item T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, item := range s {
+		k := key(item)
+		result[k] = append(result[k], item)
+	}
+	return result
+}
+
+// Split the elements into two new slices, the first contains the elements for which the predicate
+// returns true and the second contains the rest. The element order is preserved and s is not modified.
+func SlicePartition[T any](s []T, predicate func(item T) bool) ([]T, []T) {
+	matched := make([]T, 0)
+	rest := make([]T, 0)
+	for _, item := range s {
+		if predicate(item) {
+			matched = append(matched, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	return matched, rest
+}
+
+// Return a new map with the number of elements for every key returned by the key function.
+func SliceCountBy[T any, K comparable](s []T, key func(item T) K) map[K]int {
+	result := make(map[K]int)
+	for _, item := range s {
+		result[key(item)]++
+	}
+	return result
+}
+
+// Return a new map of the elements indexed by the key function.
+// If more than one element has the same key then the last element is kept.
+func SliceKeyBy[T any, K comparable](s []T, key func(item T) K) map[K]T {
+	result := make(map[K]T, len(s))
+	for _, item := range s {
+		result[key(item)] = item
+	}
+	return result
+}
+
+// Return a new slice with every element replaced by all the elements of the slice returned by fn.
+func SliceFlatMap[T any, U any](s []T, fn func(item T) []U) []U {
+	result := make([]U, 0, len(s))
+	for _, item := range s {
+		result = append(result, fn(item)...)
+	}
+	return result
+}
+
+//-----------------------------------------------------------------------------
+// Chunking and batching
+
+// Split the slice into consecutive chunks of n elements each. The last chunk contains the remaining
+// elements when len(s) is not a multiple of n.
+// The chunks are sub-slices of s (no elements are copied) with their capacity limited to their length,
+// so appending to a chunk will not overwrite the next chunk.
+// Panics if n < 1.
+func SliceChunk[T any](s []T, n int) [][]T {
+	return slices.Collect(SliceChunkSeq(s, n))
+}
+
+// Return an iterator over consecutive chunks of n elements each, see [SliceChunk].
+// Panics if n < 1.
+func SliceChunkSeq[T any](s []T, n int) iter.Seq[[]T] {
+	if n < 1 {
+		panic("chunk size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		for lo := 0; lo < len(s); lo += n {
+			hi := min(lo+n, len(s))
+			if !yield(s[lo:hi:hi]) {
+				return
+			}
+		}
+	}
+}
+
+// Return the windows of size consecutive elements, where each next window starts step elements
+// after the previous window. Only full windows are returned, which means elements at the end of the
+// slice that don't fill a window are not included and no windows are returned if len(s) < size.
+// When step > size the elements between the windows are skipped.
+// The windows are sub-slices of s (no elements are copied) with their capacity limited to their length.
+// Panics if size < 1 or step < 1.
+func SliceSlidingWindow[T any](s []T, size int, step int) [][]T {
+	return slices.Collect(SliceSlidingWindowSeq(s, size, step))
+}
+
+// Return an iterator over the windows of size consecutive elements, see [SliceSlidingWindow].
+// Panics if size < 1 or step < 1.
+func SliceSlidingWindowSeq[T any](s []T, size int, step int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("window size must be at least 1")
+	}
+	if step < 1 {
+		panic("window step must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		for lo := 0; lo+size <= len(s); lo += step {
+			hi := lo + size
+			if !yield(s[lo:hi:hi]) {
+				return
+			}
+		}
+	}
+}
+
+// Split the slice into consecutive batches where the total weight of each batch does not exceed maxWeight.
+// Batches are filled greedily in order. An element that on its own weighs more than maxWeight is
+// returned in a batch of its own, so no elements are ever dropped.
+// The batches are sub-slices of s (no elements are copied) with their capacity limited to their length.
+func SliceBatchBy[T any, W Number](s []T, weight func(item T) W, maxWeight W) [][]T {
+	return slices.Collect(SliceBatchBySeq(s, weight, maxWeight))
+}
+
+// Return an iterator over consecutive batches with a total weight that does not exceed maxWeight,
+// see [SliceBatchBy].
+func SliceBatchBySeq[T any, W Number](s []T, weight func(item T) W, maxWeight W) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		lo := 0
+		var total W
+		for i, item := range s {
+			w := weight(item)
+			if i > lo && total+w > maxWeight {
+				if !yield(s[lo:i:i]) {
+					return
+				}
+				lo, total = i, 0
+			}
+			total += w
+		}
+		if lo < len(s) {
+			yield(s[lo:len(s):len(s)])
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// De-duplication
+
+// Return a new slice that contains every element of s only once, in the order of their first occurrence.
+func SliceUnique[T comparable](s []T) []T {
+	return SliceUniqueBy(s, func(item T) T { return item })
+}
+
+// Return a new slice that only contains the first element for each distinct key returned by the key function.
+// The element order is preserved.
+func SliceUniqueBy[T any, K comparable](s []T, key func(item T) K) []T {
+	seen := make(map[K]struct{}, len(s))
+	result := make([]T, 0, len(s))
+	for _, item := range s {
+		k := key(item)
+		if _, exists := seen[k]; !exists {
+			seen[k] = struct{}{}
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Remove consecutive duplicate elements from a sorted slice so that every element appears only once.
+// Unsorted slices only have their consecutive runs of equal elements removed.
+// NOTE: The underlying array is modified and the elements past the new length are zeroed.
+func SliceCompactUnique[T comparable](s []T) []T {
+	return slices.Compact(s)
+}
+
+// Return the elements that appear more than once in s together with the number of times they appear.
+// The elements are returned in the order of their first occurrence.
+func SliceDuplicates[T comparable](s []T) []KeyValue[T, int] {
+	counts := make(map[T]int, len(s))
+	for _, item := range s {
+		counts[item]++
+	}
+
+	result := make([]KeyValue[T, int], 0)
+	for _, item := range s {
+		if n := counts[item]; n > 1 {
+			result = append(result, KeyValue[T, int]{Key: item, Value: n})
+			// Only report the element once
+			counts[item] = 0
+		}
+	}
+	return result
+}
+
 //-----------------------------------------------------------------------------
 // Common slice/array operations
 
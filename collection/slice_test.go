@@ -20,6 +20,10 @@
 package collection_test
 
 import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/andrejacobs/go-collection/collection"
@@ -75,6 +79,239 @@ func TestSafeRemoveAtFast(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSliceMap(t *testing.T) {
+	s := []int{1, 2, 3}
+	assert.Equal(t, []string{"1", "2", "3"}, collection.SliceMap(s, strconv.Itoa))
+	assert.Equal(t, []string{}, collection.SliceMap([]int{}, strconv.Itoa))
+}
+
+func TestSliceFilter(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	even := func(i int) bool { return i%2 == 0 }
+
+	assert.Equal(t, []int{0, 2, 4, 6, 8}, collection.SliceFilter(s, even))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, s)
+	assert.Equal(t, []int{}, collection.SliceFilter(s, func(int) bool { return false }))
+
+	filtered := collection.SliceFilterInPlace(s, even)
+	assert.Equal(t, []int{0, 2, 4, 6, 8}, filtered)
+	assert.Equal(t, []int{0, 2, 4, 6, 8, 0, 0, 0, 0, 0}, s)
+	assert.Empty(t, collection.SliceFilterInPlace([]int(nil), even))
+}
+
+func TestSliceReduce(t *testing.T) {
+	s := []string{"a", "bb", "ccc"}
+	total := collection.SliceReduce(s, 0, func(sum int, s string) int { return sum + len(s) })
+	assert.Equal(t, 6, total)
+	assert.Equal(t, 42, collection.SliceReduce([]string{}, 42, func(sum int, s string) int { return sum + len(s) }))
+}
+
+func TestSliceGroupByCountByKeyBy(t *testing.T) {
+	s := []string{"apple", "avocado", "banana", "blueberry", "cherry"}
+	first := func(s string) byte { return s[0] }
+
+	assert.Equal(t, map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}, collection.SliceGroupBy(s, first))
+	assert.Equal(t, map[byte]int{'a': 2, 'b': 2, 'c': 1}, collection.SliceCountBy(s, first))
+	assert.Equal(t, map[byte]string{'a': "avocado", 'b': "blueberry", 'c': "cherry"}, collection.SliceKeyBy(s, first))
+	assert.Empty(t, collection.SliceGroupBy([]string{}, first))
+}
+
+func TestSlicePartition(t *testing.T) {
+	s := []int{5, 1, 8, 3, 6}
+	big, small := collection.SlicePartition(s, func(i int) bool { return i > 4 })
+	assert.Equal(t, []int{5, 8, 6}, big)
+	assert.Equal(t, []int{1, 3}, small)
+
+	big, small = collection.SlicePartition([]int{}, func(i int) bool { return i > 4 })
+	assert.Equal(t, []int{}, big)
+	assert.Equal(t, []int{}, small)
+}
+
+func TestSliceFlatMap(t *testing.T) {
+	s := []string{"a b", "", "c d e"}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, collection.SliceFlatMap(s, strings.Fields))
+	assert.Equal(t, []string{}, collection.SliceFlatMap([]string{}, strings.Fields))
+}
+
+func TestSliceChunk(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}, collection.SliceChunk(s, 3))
+	assert.Equal(t, [][]int{{1, 2, 3, 4, 5, 6, 7}}, collection.SliceChunk(s, 7))
+	assert.Equal(t, [][]int{{1, 2, 3, 4, 5, 6, 7}}, collection.SliceChunk(s, 100))
+	assert.Empty(t, collection.SliceChunk([]int{}, 3))
+	require.Panics(t, func() { collection.SliceChunk(s, 0) })
+
+	// Chunks share the underlying array but appending must not overwrite the next chunk
+	chunks := collection.SliceChunk(s, 3)
+	chunks[0][0] = 100
+	_ = append(chunks[0], 200)
+	assert.Equal(t, []int{100, 2, 3, 4, 5, 6, 7}, s)
+
+	count := 0
+	for chunk := range collection.SliceChunkSeq(s, 2) {
+		assert.Len(t, chunk, 2)
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, count)
+}
+
+func TestSliceSlidingWindow(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6}
+	assert.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}, collection.SliceSlidingWindow(s, 3, 1))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5, 6}}, collection.SliceSlidingWindow(s, 2, 2))
+	assert.Equal(t, [][]int{{1, 2, 3, 4}, {3, 4, 5, 6}}, collection.SliceSlidingWindow(s, 4, 2))
+	assert.Equal(t, [][]int{{1, 2, 3, 4}}, collection.SliceSlidingWindow(s, 4, 3))
+	assert.Equal(t, [][]int{{1}, {4}}, collection.SliceSlidingWindow(s, 1, 3))
+	assert.Empty(t, collection.SliceSlidingWindow(s, 7, 1))
+	require.Panics(t, func() { collection.SliceSlidingWindow(s, 0, 1) })
+	require.Panics(t, func() { collection.SliceSlidingWindow(s, 1, 0) })
+
+	windows := 0
+	for range collection.SliceSlidingWindowSeq(s, 2, 1) {
+		windows++
+		break
+	}
+	assert.Equal(t, 1, windows)
+}
+
+func TestSliceBatchBy(t *testing.T) {
+	s := []string{"aa", "bbb", "c", "dddddd", "ee", "f", "g"}
+	batches := collection.SliceBatchBy(s, func(s string) int { return len(s) }, 5)
+	assert.Equal(t, [][]string{{"aa", "bbb"}, {"c"}, {"dddddd"}, {"ee", "f", "g"}}, batches)
+
+	assert.Empty(t, collection.SliceBatchBy([]string{}, func(s string) int { return len(s) }, 5))
+
+	weights := []float64{0.5, 0.5, 0.25, 1}
+	assert.Equal(t, [][]float64{{0.5, 0.5}, {0.25}, {1}},
+		collection.SliceBatchBy(weights, func(f float64) float64 { return f }, 1.0))
+
+	count := 0
+	for range collection.SliceBatchBySeq(s, func(s string) int { return len(s) }, 5) {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestSliceUnique(t *testing.T) {
+	s := []int{5, 3, 5, 1, 3, 9, 5}
+	assert.Equal(t, []int{5, 3, 1, 9}, collection.SliceUnique(s))
+	assert.Equal(t, []int{5, 3, 5, 1, 3, 9, 5}, s)
+	assert.Equal(t, []int{}, collection.SliceUnique([]int{}))
+
+	words := []string{"Apple", "avocado", "apple", "Banana", "banana"}
+	assert.Equal(t, []string{"Apple", "avocado", "Banana"}, collection.SliceUniqueBy(words, strings.ToLower))
+}
+
+func TestSliceCompactUnique(t *testing.T) {
+	s := []int{1, 1, 2, 3, 3, 3, 4}
+	assert.Equal(t, []int{1, 2, 3, 4}, collection.SliceCompactUnique(s))
+	assert.Equal(t, []int{1, 2, 3, 4, 0, 0, 0}, s)
+	assert.Empty(t, collection.SliceCompactUnique([]int{}))
+}
+
+func TestSliceDuplicates(t *testing.T) {
+	s := []string{"b", "a", "b", "c", "a", "b"}
+	assert.Equal(t, []collection.KeyValue[string, int]{{Key: "b", Value: 3}, {Key: "a", Value: 2}},
+		collection.SliceDuplicates(s))
+	assert.Empty(t, collection.SliceDuplicates([]string{"a", "b"}))
+}
+
+func TestSliceRemoveIf(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, []int{1, 3, 5, 7, 9}, collection.SliceRemoveIf(s, even))
+	assert.Equal(t, []int{1, 3, 5, 7, 9, 0, 0, 0, 0, 0}, s)
+
+	s = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	result := collection.SliceRemoveIfFast(s, even)
+	assert.Equal(t, []int{9, 1, 7, 3, 5}, result)
+	assert.Equal(t, []int{9, 1, 7, 3, 5, 0, 0, 0, 0, 0}, s)
+
+	assert.Empty(t, collection.SliceRemoveIfFast([]int{2, 4, 6}, even))
+	assert.Equal(t, []int{1, 3}, collection.SliceRemoveIfFast([]int{1, 3}, even))
+	assert.Empty(t, collection.SliceRemoveIfFast([]int{}, even))
+}
+
+func TestSliceRetainIf(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, []int{0, 2, 4, 6, 8}, collection.SliceRetainIf(s, even))
+
+	s = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	result := collection.SliceRetainIfFast(s, even)
+	assert.ElementsMatch(t, []int{0, 2, 4, 6, 8}, result)
+	assert.Equal(t, []int{0, 0, 0, 0, 0}, s[5:])
+}
+
+func TestSliceRemoveIndices(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, []int{0, 2, 3, 5, 6, 8}, collection.SliceRemoveIndices(s, 9, 1, 4, 7, 4, 9))
+	assert.Equal(t, []int{0, 2, 3, 5, 6, 8, 0, 0, 0, 0}, s)
+
+	s = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	result := collection.SliceRemoveIndicesFast(s, 9, 1, 4, 7, 4, 9)
+	assert.ElementsMatch(t, []int{0, 2, 3, 5, 6, 8}, result)
+	assert.Equal(t, []int{0, 0, 0, 0}, s[6:])
+
+	assert.Equal(t, []int{1, 2}, collection.SliceRemoveIndices([]int{1, 2}))
+	assert.Equal(t, []int{1, 2}, collection.SliceRemoveIndicesFast([]int{1, 2}))
+	assert.Empty(t, collection.SliceRemoveIndices([]int{1, 2}, 0, 1))
+	assert.Empty(t, collection.SliceRemoveIndicesFast([]int{1, 2}, 1, 0))
+
+	require.Panics(t, func() { collection.SliceRemoveIndices([]int{1, 2}, 2) })
+	require.Panics(t, func() { collection.SliceRemoveIndicesFast([]int{1, 2}, -1) })
+}
+
+func TestSliceRemoveIndicesFastMatchesOrdered(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 200; i++ {
+		n := rnd.IntN(20) + 1
+		s := make([]int, n)
+		for j := range s {
+			s[j] = j
+		}
+		indices := make([]int, rnd.IntN(n+5))
+		for j := range indices {
+			indices[j] = rnd.IntN(n)
+		}
+
+		ordered := collection.SliceRemoveIndices(slices.Clone(s), indices...)
+		fast := collection.SliceRemoveIndicesFast(slices.Clone(s), indices...)
+		require.ElementsMatch(t, ordered, fast, "indices %v", indices)
+	}
+}
+
+func TestSliceRemoveRange(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, []int{0, 1, 5, 6, 7, 8, 9}, collection.SliceRemoveRange(s, 2, 5))
+	assert.Equal(t, []int{0, 1, 5, 6, 7, 8, 9, 0, 0, 0}, s)
+
+	s = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, []int{0, 1, 8, 9, 4, 5, 6, 7}, collection.SliceRemoveRangeFast(s, 2, 4))
+	assert.Equal(t, []int{0, 0}, s[8:])
+
+	s = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, []int{0, 1, 8, 9}, collection.SliceRemoveRangeFast(s, 2, 8))
+
+	s = []int{0, 1, 2, 3, 4}
+	assert.Equal(t, []int{0, 1}, collection.SliceRemoveRangeFast(s, 2, 5))
+	assert.Equal(t, []int{0, 1}, collection.SliceRemoveRangeFast([]int{0, 1}, 1, 1))
+	assert.Empty(t, collection.SliceRemoveRange([]int{0, 1}, 0, 2))
+
+	require.Panics(t, func() { collection.SliceRemoveRange([]int{0, 1}, 1, 3) })
+	require.Panics(t, func() { collection.SliceRemoveRangeFast([]int{0, 1}, 2, 1) })
+}
+
 func BenchmarkRemoveAt(b *testing.B) {
 	const maxItems = 1000
 	scenarios := []struct {
@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import "slices"
+
+// See https://en.wikipedia.org/wiki/Minimum_spanning_tree for the algorithms used.
+
+// Kruskal returns the edges of a minimum spanning forest and their total weight using Kruskal's algorithm
+// in O(E log E) time.
+// The graph is treated as undirected, i.e. a -> b and b -> a are considered to be the same edge.
+// If the graph is not connected then the result contains a minimum spanning tree for each connected component.
+func (g *WeightedGraph[N, W]) Kruskal() ([]WeightedEdge[N, W], W) {
+	edges := g.Edges()
+	slices.SortStableFunc(edges, func(a WeightedEdge[N, W], b WeightedEdge[N, W]) int {
+		switch {
+		case a.Weight < b.Weight:
+			return -1
+		case a.Weight > b.Weight:
+			return 1
+		}
+		return 0
+	})
+
+	components := NewDisjointSet(g.Nodes()...)
+	result := make([]WeightedEdge[N, W], 0, max(g.Len()-1, 0))
+	var total W
+	for _, e := range edges {
+		if components.Union(e.From, e.To) {
+			result = append(result, e)
+			total += e.Weight
+		}
+	}
+	return result, total
+}
+
+// Prim returns the edges of a minimum spanning forest and their total weight using Prim's algorithm
+// in O(E log V) time.
+// The graph is treated as undirected, i.e. a -> b and b -> a are considered to be the same edge.
+// If the graph is not connected then the result contains a minimum spanning tree for each connected component.
+func (g *WeightedGraph[N, W]) Prim() ([]WeightedEdge[N, W], W) {
+	result := make([]WeightedEdge[N, W], 0, max(g.Len()-1, 0))
+	var total W
+
+	inTree := NewSet[N]()
+	pq := NewPriorityQueue(func(a WeightedEdge[N, W], b WeightedEdge[N, W]) bool {
+		return a.Weight < b.Weight
+	})
+
+	visit := func(node N) {
+		inTree.Insert(node)
+		for _, to := range g.Successors(node) {
+			if !inTree.Contains(to) {
+				w, _ := g.Weight(node, to)
+				pq.Push(WeightedEdge[N, W]{From: node, To: to, Weight: w})
+			}
+		}
+		for _, from := range g.Predecessors(node) {
+			if !inTree.Contains(from) {
+				w, _ := g.Weight(from, node)
+				pq.Push(WeightedEdge[N, W]{From: from, To: node, Weight: w})
+			}
+		}
+	}
+
+	for _, start := range g.Nodes() {
+		if inTree.Contains(start) {
+			continue
+		}
+
+		visit(start)
+		for pq.Len() > 0 {
+			e, _ := pq.Pop()
+			next := e.To
+			if inTree.Contains(e.To) {
+				next = e.From
+			}
+			if inTree.Contains(next) {
+				continue
+			}
+			result = append(result, e)
+			total += e.Weight
+			visit(next)
+		}
+	}
+
+	return result, total
+}
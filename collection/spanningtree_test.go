@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimumSpanningTree(t *testing.T) {
+	g := collection.NewWeightedGraph[string, int]()
+	g.AddUndirectedEdge("a", "b", 4)
+	g.AddUndirectedEdge("a", "h", 8)
+	g.AddUndirectedEdge("b", "c", 8)
+	g.AddUndirectedEdge("b", "h", 11)
+	g.AddUndirectedEdge("c", "d", 7)
+	g.AddUndirectedEdge("c", "f", 4)
+	g.AddUndirectedEdge("c", "i", 2)
+	g.AddUndirectedEdge("d", "e", 9)
+	g.AddUndirectedEdge("d", "f", 14)
+	g.AddUndirectedEdge("e", "f", 10)
+	g.AddUndirectedEdge("f", "g", 2)
+	g.AddUndirectedEdge("g", "h", 1)
+	g.AddUndirectedEdge("g", "i", 6)
+	g.AddUndirectedEdge("h", "i", 7)
+
+	kruskal, kruskalTotal := g.Kruskal()
+	assert.Equal(t, 37, kruskalTotal)
+	assert.Len(t, kruskal, 8)
+
+	prim, primTotal := g.Prim()
+	assert.Equal(t, 37, primTotal)
+	assert.Len(t, prim, 8)
+}
+
+func TestMinimumSpanningForest(t *testing.T) {
+	g := collection.NewWeightedGraph[int, float64]()
+	g.AddEdge(1, 2, 1.5)
+	g.AddEdge(2, 3, 2.5)
+	g.AddEdge(3, 1, 0.5)
+	g.AddEdge(10, 11, 4)
+	g.AddNode(20)
+
+	edges, total := g.Kruskal()
+	assert.Len(t, edges, 3)
+	assert.Equal(t, 6.0, total)
+
+	edges, total = g.Prim()
+	assert.Len(t, edges, 3)
+	assert.Equal(t, 6.0, total)
+
+	empty := collection.NewWeightedGraph[int, int]()
+	emptyEdges, emptyTotal := empty.Kruskal()
+	assert.Empty(t, emptyEdges)
+	assert.Equal(t, 0, emptyTotal)
+}
+
+func TestMinimumSpanningTreeBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	const n = 5
+
+	for run := 0; run < 30; run++ {
+		g := collection.NewWeightedGraph[int, int]()
+		var edges []collection.WeightedEdge[int, int]
+		for a := 0; a < n; a++ {
+			for b := a + 1; b < n; b++ {
+				if rnd.IntN(3) > 0 {
+					w := rnd.IntN(20)
+					g.AddEdge(a, b, w)
+					edges = append(edges, collection.WeightedEdge[int, int]{From: a, To: b, Weight: w})
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			g.AddNode(i)
+		}
+
+		// Try every subset of edges and keep the lightest spanning forest with the most edges
+		bestEdges, bestTotal := -1, 0
+		for mask := 0; mask < 1<<len(edges); mask++ {
+			d := collection.NewDisjointSet[int]()
+			count, total, acyclic := 0, 0, true
+			for i, e := range edges {
+				if mask&(1<<i) != 0 {
+					if !d.Union(e.From, e.To) {
+						acyclic = false
+						break
+					}
+					count++
+					total += e.Weight
+				}
+			}
+			if acyclic && (count > bestEdges || (count == bestEdges && total < bestTotal)) {
+				bestEdges, bestTotal = count, total
+			}
+		}
+
+		kruskal, kruskalTotal := g.Kruskal()
+		prim, primTotal := g.Prim()
+		assert.Equal(t, bestTotal, kruskalTotal)
+		assert.Equal(t, bestTotal, primTotal)
+		assert.Len(t, kruskal, bestEdges)
+		assert.Len(t, prim, bestEdges)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"iter"
+	"slices"
+)
+
+// Stream is a lazy pipeline of operations over a sequence of items.
+// Intermediate operations like [Stream.Filter] only describe the work to be done, nothing is
+// evaluated until a terminal operation like [Stream.ToSlice] pulls the items through the pipeline.
+// Operations that change the type of the items, like [StreamMap] and [StreamChunk], or that need the
+// items to be comparable, like [StreamDistinct], are functions because Go methods can't have type parameters.
+//
+// A stream can be consumed more than once if the underlying sequence can, in which case the whole
+// pipeline is evaluated again.
+type Stream[T any] struct {
+	seq iter.Seq[T]
+}
+
+// NewStream creates a new stream over the items produced by the iterator.
+func NewStream[T any](seq iter.Seq[T]) Stream[T] {
+	return Stream[T]{seq: seq}
+}
+
+// StreamOf creates a new stream over the items.
+func StreamOf[T any](items ...T) Stream[T] {
+	return Stream[T]{seq: slices.Values(items)}
+}
+
+// StreamFromMap creates a new stream over the key-value pairs of the map in an unspecified order.
+func StreamFromMap[K comparable, V any](m map[K]V) Stream[KeyValue[K, V]] {
+	return Stream[KeyValue[K, V]]{seq: func(yield func(KeyValue[K, V]) bool) {
+		for k, v := range m {
+			if !yield(KeyValue[K, V]{Key: k, Value: v}) {
+				return
+			}
+		}
+	}}
+}
+
+// All returns an iterator over the items of the stream.
+func (s Stream[T]) All() iter.Seq[T] {
+	return s.seq
+}
+
+// Filter returns a stream that only contains the items for which the predicate returns true.
+func (s Stream[T]) Filter(predicate func(item T) bool) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		for item := range s.seq {
+			if predicate(item) && !yield(item) {
+				return
+			}
+		}
+	}}
+}
+
+// Take returns a stream that contains at most the first n items.
+// The underlying sequence is not consumed any further than needed.
+func (s Stream[T]) Take(n int) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		taken := 0
+		for item := range s.seq {
+			if !yield(item) {
+				return
+			}
+			taken++
+			if taken == n {
+				return
+			}
+		}
+	}}
+}
+
+// Skip returns a stream without the first n items.
+func (s Stream[T]) Skip(n int) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		skipped := 0
+		for item := range s.seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}}
+}
+
+// Sorted returns a stream with the items sorted using the cmp function which should return a
+// negative number when a < b, a positive number when a > b and zero when a == b.
+// The sort is stable. Unlike the other operations, all the items are buffered before the first
+// item is returned.
+func (s Stream[T]) Sorted(cmp func(a T, b T) int) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		items := slices.Collect(s.seq)
+		slices.SortStableFunc(items, cmp)
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}}
+}
+
+// ToSlice returns a new slice that contains all the items.
+func (s Stream[T]) ToSlice() []T {
+	result := slices.Collect(s.seq)
+	if result == nil {
+		result = []T{}
+	}
+	return result
+}
+
+// Count returns the number of items.
+func (s Stream[T]) Count() int {
+	count := 0
+	for range s.seq {
+		count++
+	}
+	return count
+}
+
+// Reduce combines all the items into a single value by calling fn with the result so far and
+// the next item, starting with initial.
+// Use [StreamReduce] to reduce the items into a different type.
+func (s Stream[T]) Reduce(initial T, fn func(result T, item T) T) T {
+	return StreamReduce(s, initial, fn)
+}
+
+// ForEach calls fn for every item.
+func (s Stream[T]) ForEach(fn func(item T)) {
+	for item := range s.seq {
+		fn(item)
+	}
+}
+
+// First returns the first item and true, or false if the stream is empty.
+func (s Stream[T]) First() (T, bool) {
+	for item := range s.seq {
+		return item, true
+	}
+	var zero T
+	return zero, false
+}
+
+//-----------------------------------------------------------------------------
+
+// StreamMap returns a stream with every item replaced by the result of fn.
+func StreamMap[T any, U any](s Stream[T], fn func(item T) U) Stream[U] {
+	return Stream[U]{seq: func(yield func(U) bool) {
+		for item := range s.seq {
+			if !yield(fn(item)) {
+				return
+			}
+		}
+	}}
+}
+
+// StreamFlatMap returns a stream with every item replaced by all the items produced by the iterator
+// returned from fn.
+func StreamFlatMap[T any, U any](s Stream[T], fn func(item T) iter.Seq[U]) Stream[U] {
+	return Stream[U]{seq: func(yield func(U) bool) {
+		for item := range s.seq {
+			for mapped := range fn(item) {
+				if !yield(mapped) {
+					return
+				}
+			}
+		}
+	}}
+}
+
+// StreamDistinct returns a stream that only contains the first occurrence of every item.
+func StreamDistinct[T comparable](s Stream[T]) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		seen := NewSet[T]()
+		for item := range s.seq {
+			if seen.Insert(item) && !yield(item) {
+				return
+			}
+		}
+	}}
+}
+
+// StreamChunk returns a stream of consecutive non-overlapping chunks with size items each.
+// The last chunk may have fewer items. Each chunk is a new slice.
+// Panics if size < 1.
+func StreamChunk[T any](s Stream[T], size int) Stream[[]T] {
+	if size < 1 {
+		panic("chunk size must be at least 1")
+	}
+	return Stream[[]T]{seq: func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for item := range s.seq {
+			chunk = append(chunk, item)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}}
+}
+
+// StreamWindow returns a stream of sliding windows with size consecutive items each, advancing by one item
+// at a time. No windows are returned if the stream has fewer than size items. Each window is a new slice.
+// Panics if size < 1.
+func StreamWindow[T any](s Stream[T], size int) Stream[[]T] {
+	if size < 1 {
+		panic("window size must be at least 1")
+	}
+	return Stream[[]T]{seq: func(yield func([]T) bool) {
+		window := make([]T, 0, size)
+		for item := range s.seq {
+			if len(window) == size {
+				window = slices.Clone(window[1:])
+				window = slices.Grow(window, 1)
+			}
+			window = append(window, item)
+			if len(window) == size && !yield(window) {
+				return
+			}
+		}
+	}}
+}
+
+// StreamReduce combines all the items into a single value by calling fn with the result so far and
+// the next item, starting with initial.
+func StreamReduce[T any, U any](s Stream[T], initial U, fn func(result U, item T) U) U {
+	result := initial
+	for item := range s.seq {
+		result = fn(result, item)
+	}
+	return result
+}
+
+// StreamToSet returns a new set that contains the unique items.
+func StreamToSet[T comparable](s Stream[T]) Set[T] {
+	result := NewSet[T]()
+	for item := range s.seq {
+		result.Insert(item)
+	}
+	return result
+}
+
+// StreamToMap returns a new map with an entry for every item using the key and value functions.
+// If more than one item has the same key then the value of the last item is kept.
+func StreamToMap[T any, K comparable, V any](s Stream[T], key func(item T) K, value func(item T) V) map[K]V {
+	result := make(map[K]V)
+	for item := range s.seq {
+		result[key(item)] = value(item)
+	}
+	return result
+}
+
+// StreamGroupBy returns a new map where the items are grouped by the key function.
+// The items in each group are in the order they appeared in the stream.
+func StreamGroupBy[T any, K comparable](s Stream[T], key func(item T) K) map[K][]T {
+	result := make(map[K][]T)
+	for item := range s.seq {
+		k := key(item)
+		result[k] = append(result[k], item)
+	}
+	return result
+}
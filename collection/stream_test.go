@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamFilterTakeSkip(t *testing.T) {
+	s := collection.StreamOf(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+	even := s.Filter(func(i int) bool { return i%2 == 0 })
+	assert.Equal(t, []int{2, 4, 6, 8, 10}, even.ToSlice())
+	assert.Equal(t, []int{4, 6}, even.Skip(1).Take(2).ToSlice())
+	assert.Equal(t, []int{}, even.Take(0).ToSlice())
+	assert.Equal(t, []int{}, even.Skip(100).ToSlice())
+	assert.Equal(t, 10, s.Count())
+
+	first, ok := even.First()
+	assert.True(t, ok)
+	assert.Equal(t, 2, first)
+	_, ok = even.Skip(5).First()
+	assert.False(t, ok)
+}
+
+func TestStreamIsLazy(t *testing.T) {
+	pulled := 0
+	numbers := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	// An infinite sequence can be used as long as the pipeline is bounded
+	s := collection.NewStream(numbers).Filter(func(i int) bool { return i%3 == 0 }).Take(3)
+	assert.Equal(t, 0, pulled)
+	assert.Equal(t, []int{0, 3, 6}, s.ToSlice())
+	assert.Equal(t, 7, pulled)
+}
+
+func TestStreamMapFlatMap(t *testing.T) {
+	words := collection.StreamOf("a b", "c", "", "d e f")
+
+	lengths := collection.StreamMap(words, func(s string) int { return len(s) })
+	assert.Equal(t, []int{3, 1, 0, 5}, lengths.ToSlice())
+
+	split := collection.StreamFlatMap(words, func(s string) iter.Seq[string] {
+		return slices.Values(strings.Fields(s))
+	})
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, split.ToSlice())
+	assert.Equal(t, []string{"a", "b"}, split.Take(2).ToSlice())
+}
+
+func TestStreamDistinctSorted(t *testing.T) {
+	s := collection.StreamOf(5, 3, 5, 1, 3, 9)
+	assert.Equal(t, []int{5, 3, 1, 9}, collection.StreamDistinct(s).ToSlice())
+	assert.Equal(t, []int{1, 3, 3, 5, 5, 9}, s.Sorted(cmp.Compare[int]).ToSlice())
+
+	// Sorting is stable
+	type item struct {
+		key   int
+		order int
+	}
+	items := collection.StreamOf(item{2, 0}, item{1, 1}, item{2, 2}, item{1, 3})
+	sorted := items.Sorted(func(a, b item) int { return cmp.Compare(a.key, b.key) }).ToSlice()
+	assert.Equal(t, []item{{1, 1}, {1, 3}, {2, 0}, {2, 2}}, sorted)
+}
+
+func TestStreamChunkWindow(t *testing.T) {
+	s := collection.StreamOf(1, 2, 3, 4, 5)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, collection.StreamChunk(s, 2).ToSlice())
+	assert.Equal(t, [][]int{{1, 2, 3, 4, 5}}, collection.StreamChunk(s, 10).ToSlice())
+	assert.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, collection.StreamWindow(s, 3).ToSlice())
+	assert.Equal(t, [][]int{}, collection.StreamWindow(s, 6).ToSlice())
+	assert.Equal(t, [][]int{{1, 2}}, collection.StreamWindow(s, 2).Take(1).ToSlice())
+	require.Panics(t, func() { collection.StreamChunk(s, 0) })
+	require.Panics(t, func() { collection.StreamWindow(s, 0) })
+}
+
+func TestStreamTerminals(t *testing.T) {
+	s := collection.StreamOf("apple", "avocado", "banana", "blueberry", "cherry", "apple")
+
+	set := collection.StreamToSet(s)
+	assert.Equal(t, 5, set.Len())
+	assert.True(t, set.Contains("cherry"))
+
+	byLetter := collection.StreamGroupBy(s, func(s string) byte { return s[0] })
+	assert.Equal(t, map[byte][]string{
+		'a': {"apple", "avocado", "apple"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}, byLetter)
+
+	lengths := collection.StreamToMap(s, func(s string) string { return s }, func(s string) int { return len(s) })
+	assert.Equal(t, map[string]int{"apple": 5, "avocado": 7, "banana": 6, "blueberry": 9, "cherry": 6}, lengths)
+
+	total := collection.StreamReduce(s, 0, func(sum int, s string) int { return sum + len(s) })
+	assert.Equal(t, 38, total)
+	assert.Equal(t, "apple,avocado", s.Take(2).Reduce("", func(result, s string) string {
+		if result == "" {
+			return s
+		}
+		return result + "," + s
+	}))
+
+	var visited []string
+	s.Take(2).ForEach(func(s string) { visited = append(visited, s) })
+	assert.Equal(t, []string{"apple", "avocado"}, visited)
+}
+
+func TestStreamFromSetAndMap(t *testing.T) {
+	set := collection.NewSetFrom([]int{1, 2, 3, 4})
+	odd := collection.NewStream(set.All()).Filter(func(i int) bool { return i%2 == 1 })
+	assert.ElementsMatch(t, []int{1, 3}, odd.ToSlice())
+
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	big := collection.StreamFromMap(m).Filter(func(kv collection.KeyValue[string, int]) bool {
+		return kv.Value > 1
+	})
+	keys := collection.StreamMap(big, func(kv collection.KeyValue[string, int]) string { return kv.Key })
+	assert.Equal(t, []string{"b", "c"}, keys.Sorted(strings.Compare).ToSlice())
+}
+
+func BenchmarkStream(b *testing.B) {
+	items := make([]int, 10000)
+	for i := range items {
+		items[i] = i
+	}
+
+	b.Run("Stream", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := collection.StreamOf(items...).Filter(func(i int) bool { return i%2 == 0 })
+			_ = collection.StreamMap(s, func(i int) int { return i * 3 }).Count()
+		}
+	})
+
+	b.Run("Loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			count := 0
+			for _, item := range items {
+				if item%2 == 0 {
+					_ = item * 3
+					count++
+				}
+			}
+			_ = count
+		}
+	})
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// See https://github.com/tdunning/t-digest/blob/main/docs/t-digest-paper/histo.pdf for the t-digest paper.
+
+// DefaultTDigestCompression is the compression used by [NewTDigest] when a value <= 0 is specified.
+// Higher values keep more centroids which improves accuracy at the cost of memory.
+const DefaultTDigestCompression = 100
+
+// maxTDigestCompression is the largest compression supported, higher values are limited to it.
+const maxTDigestCompression = 1e6
+
+// tdigestPreallocate is the most centroids that are allocated upfront, digests with a higher
+// compression grow their slices as needed.
+const tdigestPreallocate = 4096
+
+// tdigestVersion is written as the first byte of the binary encoding.
+const tdigestVersion = 1
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest is a streaming sketch that estimates quantiles and the cumulative distribution
+// of float64 values using a bounded amount of memory.
+// Accuracy is highest near the extreme quantiles (e.g. p1 and p99).
+// A TDigest is not safe for concurrent use, instead use one digest per goroutine and
+// combine them using [TDigest.Merge].
+// The zero value is not usable, create a new digest using [NewTDigest].
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	bufferSize  int // number of buffered values that triggers a compress
+	count       float64
+	min         float64
+	max         float64
+}
+
+// NewTDigest creates a new t-digest using the specified compression.
+// If compression is <= 0 or NaN then [DefaultTDigestCompression] will be used.
+// Compression is limited to a maximum of 1e6.
+func NewTDigest(compression float64) *TDigest {
+	if !(compression > 0) {
+		compression = DefaultTDigestCompression
+	}
+	compression = math.Min(compression, maxTDigestCompression)
+	bufferSize := int(compression) * 4
+	return &TDigest{
+		compression: compression,
+		centroids:   make([]centroid, 0, min(int(compression), tdigestPreallocate)),
+		buffer:      make([]centroid, 0, min(bufferSize, tdigestPreallocate)),
+		bufferSize:  bufferSize,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Compression returns the compression factor used by the digest.
+func (t *TDigest) Compression() float64 {
+	return t.compression
+}
+
+// Count returns the total weight of all the values added to the digest.
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+// Min returns the smallest value added to the digest or NaN if the digest is empty.
+func (t *TDigest) Min() float64 {
+	if t.count == 0 {
+		return math.NaN()
+	}
+	return t.min
+}
+
+// Max returns the largest value added to the digest or NaN if the digest is empty.
+func (t *TDigest) Max() float64 {
+	if t.count == 0 {
+		return math.NaN()
+	}
+	return t.max
+}
+
+// Add a single value to the digest.
+func (t *TDigest) Add(x float64) {
+	t.AddWeighted(x, 1)
+}
+
+// AddWeighted adds the value x as if it was added weight number of times.
+// NaN values and weights <= 0 are ignored.
+func (t *TDigest) AddWeighted(x float64, weight float64) {
+	if math.IsNaN(x) || !(weight > 0) {
+		return
+	}
+
+	t.buffer = append(t.buffer, centroid{mean: x, weight: weight})
+	t.count += weight
+	t.min = math.Min(t.min, x)
+	t.max = math.Max(t.max, x)
+
+	if len(t.buffer) >= t.bufferSize {
+		t.compress()
+	}
+}
+
+// Merge adds all of the values summarised by other into this digest.
+// The other digest is not modified.
+func (t *TDigest) Merge(other *TDigest) {
+	if other == nil || other.count == 0 {
+		return
+	}
+
+	// Read both slices of other before appending in case other is t
+	centroids, buffer := other.centroids, other.buffer
+	t.buffer = append(t.buffer, centroids...)
+	t.buffer = append(t.buffer, buffer...)
+	t.count += other.count
+	t.min = math.Min(t.min, other.min)
+	t.max = math.Max(t.max, other.max)
+	t.compress()
+}
+
+// Quantile returns the estimated value at quantile q where 0 <= q <= 1.
+// For example Quantile(0.99) returns the estimated p99.
+// Returns NaN if the digest is empty or q is not within [0, 1].
+func (t *TDigest) Quantile(q float64) float64 {
+	if t.count == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+	t.compress()
+
+	if q == 0 {
+		return t.min
+	}
+	if q == 1 {
+		return t.max
+	}
+
+	c := t.centroids
+	n := len(c)
+	if n == 1 {
+		return c[0].mean
+	}
+
+	index := q * t.count
+
+	// Left tail between the minimum and the first centroid
+	if index < c[0].weight/2 {
+		return t.min + (index/(c[0].weight/2))*(c[0].mean-t.min)
+	}
+
+	weightSoFar := c[0].weight / 2
+	for i := 0; i < n-1; i++ {
+		dw := (c[i].weight + c[i+1].weight) / 2
+		if weightSoFar+dw > index {
+			frac := (index - weightSoFar) / dw
+			return c[i].mean + frac*(c[i+1].mean-c[i].mean)
+		}
+		weightSoFar += dw
+	}
+
+	// Right tail between the last centroid and the maximum
+	last := c[n-1]
+	frac := math.Min((index-weightSoFar)/(last.weight/2), 1)
+	return last.mean + frac*(t.max-last.mean)
+}
+
+// CDF returns the estimated fraction of values that are <= x.
+// Returns NaN if the digest is empty.
+func (t *TDigest) CDF(x float64) float64 {
+	if t.count == 0 || math.IsNaN(x) {
+		return math.NaN()
+	}
+	t.compress()
+
+	if x < t.min {
+		return 0
+	}
+	if x >= t.max {
+		return 1
+	}
+
+	c := t.centroids
+	n := len(c)
+	if n == 1 {
+		return (x - t.min) / (t.max - t.min)
+	}
+
+	// Left tail between the minimum and the first centroid
+	if x < c[0].mean {
+		return (c[0].weight / 2) * (x - t.min) / (c[0].mean - t.min) / t.count
+	}
+
+	weightSoFar := c[0].weight / 2
+	for i := 0; i < n-1; i++ {
+		dw := (c[i].weight + c[i+1].weight) / 2
+		if x < c[i+1].mean {
+			return (weightSoFar + dw*(x-c[i].mean)/(c[i+1].mean-c[i].mean)) / t.count
+		}
+		weightSoFar += dw
+	}
+
+	// Right tail between the last centroid and the maximum
+	last := c[n-1]
+	return (weightSoFar + (last.weight/2)*(x-last.mean)/(t.max-last.mean)) / t.count
+}
+
+// Centroids returns the number of centroids used to summarise the values.
+func (t *TDigest) Centroids() int {
+	t.compress()
+	return len(t.centroids)
+}
+
+// Reset removes all values from the digest while keeping the compression.
+func (t *TDigest) Reset() {
+	t.centroids = t.centroids[:0]
+	t.buffer = t.buffer[:0]
+	t.count = 0
+	t.min = math.Inf(1)
+	t.max = math.Inf(-1)
+}
+
+// MarshalBinary encodes the digest into a compact binary form.
+// Implements the [encoding.BinaryMarshaler] interface.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+
+	buf := make([]byte, 0, 1+8*3+binary.MaxVarintLen64+len(t.centroids)*16)
+	buf = append(buf, tdigestVersion)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(t.compression))
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(t.min))
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(t.max))
+	buf = binary.AppendUvarint(buf, uint64(len(t.centroids)))
+	for _, c := range t.centroids {
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(c.mean))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(c.weight))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a digest previously encoded with [TDigest.MarshalBinary].
+// Implements the [encoding.BinaryUnmarshaler] interface.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+8*3 {
+		return fmt.Errorf("t-digest data is too short (%d bytes)", len(data))
+	}
+	if data[0] != tdigestVersion {
+		return fmt.Errorf("unsupported t-digest version %d", data[0])
+	}
+
+	readFloat := func(b []byte) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+
+	compression := readFloat(data[1:])
+	minValue := readFloat(data[9:])
+	maxValue := readFloat(data[17:])
+	data = data[25:]
+	if !(compression > 0 && compression <= maxTDigestCompression) {
+		return fmt.Errorf("invalid t-digest compression %v", compression)
+	}
+
+	n, read := binary.Uvarint(data)
+	if read <= 0 {
+		return fmt.Errorf("invalid t-digest centroid count")
+	}
+	data = data[read:]
+	if n > uint64(len(data))/16 || uint64(len(data)) != n*16 {
+		return fmt.Errorf("expected %d centroids but found %d bytes of t-digest centroid data", n, len(data))
+	}
+
+	decoded := NewTDigest(compression)
+	decoded.centroids = make([]centroid, 0, n)
+	for i := uint64(0); i < n; i++ {
+		c := centroid{mean: readFloat(data), weight: readFloat(data[8:])}
+		data = data[16:]
+		if math.IsNaN(c.mean) || !(c.weight > 0) || math.IsInf(c.weight, 1) {
+			return fmt.Errorf("invalid t-digest centroid %d", i)
+		}
+		if i > 0 && c.mean < decoded.centroids[i-1].mean {
+			return fmt.Errorf("t-digest centroid %d is not sorted by mean", i)
+		}
+		decoded.centroids = append(decoded.centroids, c)
+		decoded.count += c.weight
+	}
+	if math.IsInf(decoded.count, 1) {
+		return fmt.Errorf("invalid t-digest total weight")
+	}
+	if n > 0 {
+		if !(minValue <= decoded.centroids[0].mean && maxValue >= decoded.centroids[n-1].mean) {
+			return fmt.Errorf("invalid t-digest min %v and max %v", minValue, maxValue)
+		}
+		decoded.min = minValue
+		decoded.max = maxValue
+	}
+
+	*t = *decoded
+	return nil
+}
+
+// compress merges the buffered values into the centroids.
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	all := make([]centroid, 0, len(t.buffer)+len(t.centroids))
+	all = append(all, t.centroids...)
+	all = append(all, t.buffer...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+
+	merged := make([]centroid, 0, len(t.centroids)+1)
+	current := all[0]
+	weightSoFar := 0.0
+	limit := t.quantileLimit(0)
+
+	for _, next := range all[1:] {
+		q := (weightSoFar + current.weight + next.weight) / t.count
+		if q <= limit {
+			current.weight += next.weight
+			current.mean += (next.mean - current.mean) * next.weight / current.weight
+		} else {
+			weightSoFar += current.weight
+			merged = append(merged, current)
+			limit = t.quantileLimit(weightSoFar / t.count)
+			current = next
+		}
+	}
+	merged = append(merged, current)
+
+	t.centroids = merged
+	t.buffer = t.buffer[:0]
+}
+
+// quantileLimit returns the largest quantile a centroid starting at q may grow to.
+// This uses the k1 scale function k(q) = δ/2π * asin(2q - 1) that keeps centroids near
+// the tails small.
+func (t *TDigest) quantileLimit(q float64) float64 {
+	k := t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+	k++
+	if k >= t.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/t.compression) + 1) / 2
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTDigestEmpty(t *testing.T) {
+	td := collection.NewTDigest(0)
+	assert.Equal(t, float64(collection.DefaultTDigestCompression), td.Compression())
+	assert.Equal(t, 0.0, td.Count())
+	assert.True(t, math.IsNaN(td.Quantile(0.5)))
+	assert.True(t, math.IsNaN(td.CDF(42)))
+	assert.True(t, math.IsNaN(td.Min()))
+	assert.True(t, math.IsNaN(td.Max()))
+}
+
+func TestTDigestSingleValue(t *testing.T) {
+	td := collection.NewTDigest(100)
+	td.Add(42)
+	assert.Equal(t, 42.0, td.Quantile(0))
+	assert.Equal(t, 42.0, td.Quantile(0.5))
+	assert.Equal(t, 42.0, td.Quantile(1))
+	assert.Equal(t, 0.0, td.CDF(41))
+	assert.Equal(t, 1.0, td.CDF(42))
+	assert.True(t, math.IsNaN(td.Quantile(1.5)))
+}
+
+func TestTDigestQuantileAndCDF(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	td := collection.NewTDigest(200)
+	values := make([]float64, 100000)
+	for i := range values {
+		values[i] = rnd.Float64() * 1000
+		td.Add(values[i])
+	}
+	sort.Float64s(values)
+
+	assert.Equal(t, float64(len(values)), td.Count())
+	assert.Equal(t, values[0], td.Min())
+	assert.Equal(t, values[len(values)-1], td.Max())
+	assert.Less(t, td.Centroids(), 400)
+
+	for _, q := range []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999} {
+		expected := values[int(q*float64(len(values)))]
+		assert.InDelta(t, expected, td.Quantile(q), 1000*0.005, "quantile %v", q)
+		assert.InDelta(t, q, td.CDF(expected), 0.005, "cdf at quantile %v", q)
+	}
+
+	assert.Equal(t, 0.0, td.CDF(-1))
+	assert.Equal(t, 1.0, td.CDF(1000))
+}
+
+func TestTDigestMerge(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	values := make([]float64, 40000)
+	for i := range values {
+		values[i] = rnd.NormFloat64()*10 + 50
+	}
+
+	digests := make([]*collection.TDigest, 4)
+	var wg sync.WaitGroup
+	for i := range digests {
+		digests[i] = collection.NewTDigest(100)
+		wg.Add(1)
+		go func(td *collection.TDigest, part []float64) {
+			defer wg.Done()
+			for _, v := range part {
+				td.Add(v)
+			}
+		}(digests[i], values[i*10000:(i+1)*10000])
+	}
+	wg.Wait()
+
+	merged := collection.NewTDigest(100)
+	for _, td := range digests {
+		merged.Merge(td)
+	}
+
+	sort.Float64s(values)
+	assert.Equal(t, float64(len(values)), merged.Count())
+	assert.Equal(t, values[0], merged.Min())
+	assert.Equal(t, values[len(values)-1], merged.Max())
+	for _, q := range []float64{0.01, 0.5, 0.99} {
+		expected := values[int(q*float64(len(values)))]
+		assert.InDelta(t, expected, merged.Quantile(q), 0.5, "quantile %v", q)
+	}
+}
+
+func TestTDigestMergeSelf(t *testing.T) {
+	td := collection.NewTDigest(100)
+	for i := 0; i < 1000; i++ {
+		td.Add(float64(i))
+	}
+	// Leave some values in the buffer as well as in the centroids
+	td.Centroids()
+	for i := 1000; i < 1100; i++ {
+		td.Add(float64(i))
+	}
+
+	td.Merge(td)
+	assert.Equal(t, 2200.0, td.Count())
+	assert.Equal(t, 0.0, td.Min())
+	assert.Equal(t, 1099.0, td.Max())
+	assert.InDelta(t, 550, td.Quantile(0.5), 5)
+	assert.InDelta(t, 0.5, td.CDF(550), 0.01)
+}
+
+func TestTDigestWeighted(t *testing.T) {
+	td := collection.NewTDigest(100)
+	td.AddWeighted(1, 99)
+	td.AddWeighted(100, 1)
+	td.AddWeighted(math.NaN(), 1)
+	td.AddWeighted(5, 0)
+	assert.Equal(t, 100.0, td.Count())
+	assert.Equal(t, 1.0, td.Quantile(0.25))
+	assert.Equal(t, 100.0, td.Max())
+}
+
+func TestTDigestMarshalBinary(t *testing.T) {
+	td := collection.NewTDigest(50)
+	for i := 0; i < 10000; i++ {
+		td.Add(float64(i))
+	}
+
+	data, err := td.MarshalBinary()
+	require.NoError(t, err)
+	assert.Less(t, len(data), 16*200)
+
+	var decoded collection.TDigest
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, td.Compression(), decoded.Compression())
+	assert.Equal(t, td.Count(), decoded.Count())
+	assert.Equal(t, td.Min(), decoded.Min())
+	assert.Equal(t, td.Max(), decoded.Max())
+	assert.Equal(t, td.Quantile(0.99), decoded.Quantile(0.99))
+
+	// Can continue to add values after decoding
+	decoded.Add(20000)
+	assert.Equal(t, 20000.0, decoded.Max())
+
+	assert.Error(t, decoded.UnmarshalBinary(nil))
+	assert.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	data[0] = 0xFF
+	assert.Error(t, decoded.UnmarshalBinary(data))
+}
+
+func TestTDigestUnmarshalBinaryMalformed(t *testing.T) {
+	type digest struct {
+		compression float64
+		min, max    float64
+		n           uint64 // centroid count, defaults to len(centroids)
+		centroids   [][2]float64
+	}
+	encode := func(d digest) []byte {
+		if d.n == 0 {
+			d.n = uint64(len(d.centroids))
+		}
+		buf := []byte{1}
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(d.compression))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(d.min))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(d.max))
+		buf = binary.AppendUvarint(buf, d.n)
+		for _, c := range d.centroids {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(c[0]))
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(c[1]))
+		}
+		return buf
+	}
+	valid := func(change func(d *digest)) []byte {
+		d := digest{compression: 100, min: 0, max: 100, centroids: [][2]float64{{10, 1}, {50, 2}, {90, 1}}}
+		change(&d)
+		return encode(d)
+	}
+
+	var td collection.TDigest
+	require.NoError(t, td.UnmarshalBinary(valid(func(d *digest) {})))
+	assert.Equal(t, 4.0, td.Count())
+	require.NoError(t, td.UnmarshalBinary(valid(func(d *digest) { d.min, d.max = 10, 90 })))
+
+	for _, compression := range []float64{0, -1, math.NaN(), math.Inf(1), 1e300} {
+		assert.Error(t, td.UnmarshalBinary(valid(func(d *digest) { d.compression = compression })),
+			"compression %v", compression)
+	}
+
+	// n*16 wraps around to 16
+	assert.Error(t, td.UnmarshalBinary(valid(func(d *digest) { d.n, d.centroids = 1<<60+1, d.centroids[:1] })))
+	assert.Error(t, td.UnmarshalBinary(valid(func(d *digest) { d.n = math.MaxUint64 })))
+	assert.Error(t, td.UnmarshalBinary(valid(func(d *digest) { d.n = 4 })))
+	assert.Error(t, td.UnmarshalBinary(valid(func(d *digest) { d.n = 2 })))
+
+	for name, change := range map[string]func(d *digest){
+		"unsorted means":  func(d *digest) { d.centroids[1][0] = 5 },
+		"NaN mean":        func(d *digest) { d.centroids[1][0] = math.NaN() },
+		"zero weight":     func(d *digest) { d.centroids[1][1] = 0 },
+		"NaN weight":      func(d *digest) { d.centroids[1][1] = math.NaN() },
+		"infinite weight": func(d *digest) { d.centroids[1][1] = math.Inf(1) },
+		"total overflows": func(d *digest) { d.centroids[0][1], d.centroids[1][1] = math.MaxFloat64, math.MaxFloat64 },
+		"NaN min":         func(d *digest) { d.min = math.NaN() },
+		"NaN max":         func(d *digest) { d.max = math.NaN() },
+		"min above max":   func(d *digest) { d.min, d.max = 100, 0 },
+		"min above mean":  func(d *digest) { d.min = 20 },
+		"max below mean":  func(d *digest) { d.max = 80 },
+	} {
+		assert.Error(t, td.UnmarshalBinary(valid(change)), name)
+	}
+
+	// The failed attempts must not have modified the digest
+	assert.Equal(t, 4.0, td.Count())
+	assert.Equal(t, 10.0, td.Min())
+}
+
+func TestTDigestLargeCompression(t *testing.T) {
+	for _, compression := range []float64{math.Inf(1), 1e15, 1e6} {
+		td := collection.NewTDigest(compression)
+		assert.Equal(t, 1e6, td.Compression())
+		for i := 0; i < 10000; i++ {
+			td.Add(float64(i))
+		}
+		assert.InDelta(t, 5000, td.Quantile(0.5), 1)
+	}
+}
+
+func TestTDigestReset(t *testing.T) {
+	td := collection.NewTDigest(100)
+	td.Add(1)
+	td.Add(2)
+	td.Reset()
+	assert.Equal(t, 0.0, td.Count())
+	assert.Equal(t, 0, td.Centroids())
+	td.Add(5)
+	assert.Equal(t, 5.0, td.Min())
+}
+
+func BenchmarkTDigestAdd(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	td := collection.NewTDigest(100)
+	for i := 0; i < b.N; i++ {
+		td.Add(rnd.Float64())
+	}
+}
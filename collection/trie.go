@@ -0,0 +1,332 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// See https://en.wikipedia.org/wiki/Trie for the data structure.
+
+// Trie is a prefix tree that maps string keys to values of type V.
+// Keys are compared byte by byte, so iterating the trie returns the keys in the same order
+// as sorting the keys with the < operator.
+// The zero value is not usable, create a new trie using [NewTrie].
+type Trie[V any] struct {
+	t *SliceTrie[byte, V]
+}
+
+// NewTrie creates a new empty trie.
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{
+		t: NewSliceTrie[byte, V](),
+	}
+}
+
+// Len returns the number of keys stored in the trie.
+func (t *Trie[V]) Len() int {
+	return t.t.Len()
+}
+
+// Put stores the value for the key.
+// Returns true if the key is new and false if an existing value was replaced.
+func (t *Trie[V]) Put(key string, value V) bool {
+	return t.t.Put([]byte(key), value)
+}
+
+// Get returns the value for the key and true if the key exists.
+func (t *Trie[V]) Get(key string) (V, bool) {
+	return t.t.Get([]byte(key))
+}
+
+// Contains returns true if the key exists in the trie.
+func (t *Trie[V]) Contains(key string) bool {
+	return t.t.Contains([]byte(key))
+}
+
+// Delete removes the key from the trie.
+// Returns true if the key existed before being removed.
+func (t *Trie[V]) Delete(key string) bool {
+	return t.t.Delete([]byte(key))
+}
+
+// HasPrefix returns true if at least one key in the trie starts with prefix.
+func (t *Trie[V]) HasPrefix(prefix string) bool {
+	return t.t.HasPrefix([]byte(prefix))
+}
+
+// LongestPrefixMatch returns the longest key in the trie that is a prefix of key.
+// Returns false if no key in the trie is a prefix of key.
+func (t *Trie[V]) LongestPrefixMatch(key string) (string, V, bool) {
+	prefix, value, ok := t.t.LongestPrefixMatch([]byte(key))
+	return string(prefix), value, ok
+}
+
+// WalkPrefix calls fn for every key that starts with prefix in ascending key order.
+// The walk stops when fn returns false.
+func (t *Trie[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	for k, v := range t.WithPrefix(prefix) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// WithPrefix returns an iterator over the key-value pairs of every key that starts with prefix
+// in ascending key order.
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.t.walkPrefix([]byte(prefix), func(key []byte, value V) bool {
+			return yield(string(key), value)
+		})
+	}
+}
+
+// All returns an iterator over all the key-value pairs in ascending key order.
+func (t *Trie[V]) All() iter.Seq2[string, V] {
+	return t.WithPrefix("")
+}
+
+// Keys returns all the keys in ascending order.
+func (t *Trie[V]) Keys() []string {
+	result := make([]string, 0, t.Len())
+	for k := range t.All() {
+		result = append(result, k)
+	}
+	return result
+}
+
+//-----------------------------------------------------------------------------
+
+// SliceTrie is a prefix tree that maps keys made up of a sequence of symbols of type S to values of type V.
+// For example a SliceTrie[rune, V] can be used to do prefix matching per unicode code point
+// instead of per byte like [Trie].
+// The zero value is not usable, create a new trie using [NewSliceTrie].
+type SliceTrie[S cmp.Ordered, V any] struct {
+	root *trieNode[S, V]
+	len  int
+}
+
+type trieNode[S cmp.Ordered, V any] struct {
+	children []trieEdge[S, V] // sorted by symbol
+	value    V
+	hasValue bool
+}
+
+type trieEdge[S cmp.Ordered, V any] struct {
+	symbol S
+	node   *trieNode[S, V]
+}
+
+// NewSliceTrie creates a new empty trie.
+func NewSliceTrie[S cmp.Ordered, V any]() *SliceTrie[S, V] {
+	return &SliceTrie[S, V]{
+		root: &trieNode[S, V]{},
+	}
+}
+
+// Len returns the number of keys stored in the trie.
+func (t *SliceTrie[S, V]) Len() int {
+	return t.len
+}
+
+// Put stores the value for the key.
+// Returns true if the key is new and false if an existing value was replaced.
+func (t *SliceTrie[S, V]) Put(key []S, value V) bool {
+	n := t.root
+	for _, sym := range key {
+		i, found := n.find(sym)
+		if !found {
+			n.children = slices.Insert(n.children, i, trieEdge[S, V]{symbol: sym, node: &trieNode[S, V]{}})
+		}
+		n = n.children[i].node
+	}
+
+	added := !n.hasValue
+	n.value = value
+	n.hasValue = true
+	if added {
+		t.len++
+	}
+	return added
+}
+
+// Get returns the value for the key and true if the key exists.
+func (t *SliceTrie[S, V]) Get(key []S) (V, bool) {
+	n := t.root.lookup(key)
+	if n == nil || !n.hasValue {
+		var zero V
+		return zero, false
+	}
+	return n.value, true
+}
+
+// Contains returns true if the key exists in the trie.
+func (t *SliceTrie[S, V]) Contains(key []S) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// Delete removes the key from the trie.
+// Returns true if the key existed before being removed.
+func (t *SliceTrie[S, V]) Delete(key []S) bool {
+	// Keep track of the path so that nodes without values or children can be pruned
+	path := make([]*trieNode[S, V], 0, len(key)+1)
+	n := t.root
+	path = append(path, n)
+	for _, sym := range key {
+		i, found := n.find(sym)
+		if !found {
+			return false
+		}
+		n = n.children[i].node
+		path = append(path, n)
+	}
+
+	if !n.hasValue {
+		return false
+	}
+
+	var zero V
+	n.value = zero
+	n.hasValue = false
+	t.len--
+
+	for i := len(key) - 1; i >= 0; i-- {
+		child := path[i+1]
+		if child.hasValue || len(child.children) > 0 {
+			break
+		}
+		parent := path[i]
+		idx, _ := parent.find(key[i])
+		parent.children = slices.Delete(parent.children, idx, idx+1)
+	}
+
+	return true
+}
+
+// HasPrefix returns true if at least one key in the trie starts with prefix.
+func (t *SliceTrie[S, V]) HasPrefix(prefix []S) bool {
+	n := t.root.lookup(prefix)
+	return n != nil && (n.hasValue || len(n.children) > 0)
+}
+
+// LongestPrefixMatch returns the longest key in the trie that is a prefix of key.
+// Returns false if no key in the trie is a prefix of key.
+func (t *SliceTrie[S, V]) LongestPrefixMatch(key []S) ([]S, V, bool) {
+	var value V
+	length := -1
+
+	n := t.root
+	if n.hasValue {
+		value, length = n.value, 0
+	}
+
+	for i, sym := range key {
+		idx, found := n.find(sym)
+		if !found {
+			break
+		}
+		n = n.children[idx].node
+		if n.hasValue {
+			value, length = n.value, i+1
+		}
+	}
+
+	if length < 0 {
+		return nil, value, false
+	}
+	return slices.Clone(key[:length]), value, true
+}
+
+// WalkPrefix calls fn for every key that starts with prefix in ascending key order.
+// The walk stops when fn returns false.
+func (t *SliceTrie[S, V]) WalkPrefix(prefix []S, fn func(key []S, value V) bool) {
+	for k, v := range t.WithPrefix(prefix) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// WithPrefix returns an iterator over the key-value pairs of every key that starts with prefix
+// in ascending key order.
+func (t *SliceTrie[S, V]) WithPrefix(prefix []S) iter.Seq2[[]S, V] {
+	return func(yield func([]S, V) bool) {
+		t.walkPrefix(prefix, func(key []S, value V) bool {
+			return yield(slices.Clone(key), value)
+		})
+	}
+}
+
+// All returns an iterator over all the key-value pairs in ascending key order.
+func (t *SliceTrie[S, V]) All() iter.Seq2[[]S, V] {
+	return t.WithPrefix(nil)
+}
+
+// walkPrefix visits all keys starting with prefix. The key passed to fn is only valid for the
+// duration of the call.
+func (t *SliceTrie[S, V]) walkPrefix(prefix []S, fn func(key []S, value V) bool) {
+	n := t.root.lookup(prefix)
+	if n == nil {
+		return
+	}
+
+	path := make([]S, len(prefix), len(prefix)+16)
+	copy(path, prefix)
+	n.walk(path, fn)
+}
+
+// find returns the index of the child edge for the symbol or the index at which it should be inserted.
+func (n *trieNode[S, V]) find(sym S) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].symbol >= sym
+	})
+	return i, i < len(n.children) && n.children[i].symbol == sym
+}
+
+// lookup returns the node at the end of key or nil if it does not exist.
+func (n *trieNode[S, V]) lookup(key []S) *trieNode[S, V] {
+	for _, sym := range key {
+		i, found := n.find(sym)
+		if !found {
+			return nil
+		}
+		n = n.children[i].node
+	}
+	return n
+}
+
+// walk does a pre-order traversal which visits the keys in ascending order.
+// Returns false if the walk was stopped.
+func (n *trieNode[S, V]) walk(path []S, fn func(key []S, value V) bool) bool {
+	if n.hasValue && !fn(path, n.value) {
+		return false
+	}
+	for _, edge := range n.children {
+		if !edge.node.walk(append(path, edge.symbol), fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriePutGetDelete(t *testing.T) {
+	tr := collection.NewTrie[int]()
+	assert.True(t, tr.Put("apple", 1))
+	assert.True(t, tr.Put("app", 2))
+	assert.True(t, tr.Put("banana", 3))
+	assert.False(t, tr.Put("apple", 10))
+	assert.Equal(t, 3, tr.Len())
+
+	v, ok := tr.Get("apple")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+
+	_, ok = tr.Get("ap")
+	assert.False(t, ok)
+	assert.False(t, tr.Contains("apples"))
+	assert.True(t, tr.Contains("app"))
+
+	assert.True(t, tr.Delete("app"))
+	assert.False(t, tr.Delete("app"))
+	assert.False(t, tr.Delete("kiwi"))
+	assert.Equal(t, 2, tr.Len())
+	assert.True(t, tr.Contains("apple"))
+
+	assert.True(t, tr.Delete("apple"))
+	assert.False(t, tr.HasPrefix("a"))
+	assert.Equal(t, []string{"banana"}, tr.Keys())
+}
+
+func TestTrieEmptyKey(t *testing.T) {
+	tr := collection.NewTrie[string]()
+	assert.True(t, tr.Put("", "root"))
+	v, ok := tr.Get("")
+	assert.True(t, ok)
+	assert.Equal(t, "root", v)
+
+	prefix, v, ok := tr.LongestPrefixMatch("anything")
+	assert.True(t, ok)
+	assert.Equal(t, "", prefix)
+	assert.Equal(t, "root", v)
+}
+
+func TestTrieHasPrefix(t *testing.T) {
+	tr := collection.NewTrie[bool]()
+	tr.Put("/api/users", true)
+	tr.Put("/api/groups", true)
+
+	assert.True(t, tr.HasPrefix(""))
+	assert.True(t, tr.HasPrefix("/api/"))
+	assert.True(t, tr.HasPrefix("/api/users"))
+	assert.False(t, tr.HasPrefix("/api/users/42"))
+	assert.False(t, tr.HasPrefix("/web"))
+}
+
+func TestTrieOrderedIteration(t *testing.T) {
+	keys := []string{"tea", "ten", "to", "inn", "in", "i", "A", "tenant"}
+	tr := collection.NewTrie[int]()
+	for i, k := range keys {
+		tr.Put(k, i)
+	}
+
+	expected := slices.Clone(keys)
+	slices.Sort(expected)
+	assert.Equal(t, expected, tr.Keys())
+
+	all := maps.Collect(tr.All())
+	assert.Len(t, all, len(keys))
+	assert.Equal(t, 7, all["tenant"])
+
+	var walked []string
+	tr.WalkPrefix("te", func(key string, value int) bool {
+		walked = append(walked, key)
+		return true
+	})
+	assert.Equal(t, []string{"tea", "ten", "tenant"}, walked)
+
+	walked = nil
+	tr.WalkPrefix("te", func(key string, value int) bool {
+		walked = append(walked, key)
+		return len(walked) < 2
+	})
+	assert.Equal(t, []string{"tea", "ten"}, walked)
+
+	walked = nil
+	for k := range tr.WithPrefix("x") {
+		walked = append(walked, k)
+	}
+	assert.Empty(t, walked)
+}
+
+func TestTrieLongestPrefixMatch(t *testing.T) {
+	tr := collection.NewTrie[string]()
+	tr.Put("/api", "api")
+	tr.Put("/api/v1", "v1")
+	tr.Put("/api/v1/users", "users")
+
+	prefix, v, ok := tr.LongestPrefixMatch("/api/v1/users/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/api/v1/users", prefix)
+	assert.Equal(t, "users", v)
+
+	prefix, v, ok = tr.LongestPrefixMatch("/api/v2")
+	assert.True(t, ok)
+	assert.Equal(t, "/api", prefix)
+	assert.Equal(t, "api", v)
+
+	_, _, ok = tr.LongestPrefixMatch("/web")
+	assert.False(t, ok)
+}
+
+func TestSliceTrieRunes(t *testing.T) {
+	tr := collection.NewSliceTrie[rune, int]()
+	assert.True(t, tr.Put([]rune("café"), 1))
+	assert.True(t, tr.Put([]rune("cafés"), 2))
+	assert.True(t, tr.Put([]rune("über"), 3))
+	assert.Equal(t, 3, tr.Len())
+
+	v, ok := tr.Get([]rune("café"))
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.True(t, tr.HasPrefix([]rune("caf")))
+
+	var keys []string
+	for k := range tr.All() {
+		keys = append(keys, string(k))
+	}
+	assert.Equal(t, []string{"café", "cafés", "über"}, keys)
+
+	prefix, _, ok := tr.LongestPrefixMatch([]rune("cafétéria"))
+	assert.True(t, ok)
+	assert.Equal(t, "café", string(prefix))
+
+	assert.True(t, tr.Delete([]rune("cafés")))
+	assert.True(t, tr.Contains([]rune("café")))
+	assert.Equal(t, 2, tr.Len())
+}
+
+func BenchmarkTrie(b *testing.B) {
+	keys := make([]string, 0, 10000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, "/api/v1/resource/"+string(rune('a'+i%26))+string(rune('a'+i/26%26))+string(rune('a'+i/676)))
+	}
+
+	b.Run("Put", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tr := collection.NewTrie[int]()
+			for j, k := range keys {
+				tr.Put(k, j)
+			}
+		}
+	})
+
+	tr := collection.NewTrie[int]()
+	for j, k := range keys {
+		tr.Put(k, j)
+	}
+
+	b.Run("LongestPrefixMatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = tr.LongestPrefixMatch(keys[i%len(keys)] + "/extra")
+		}
+	})
+}
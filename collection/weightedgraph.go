@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import "iter"
+
+// WeightedEdge is a directed edge with a weight.
+type WeightedEdge[N comparable, W Number] struct {
+	From   N
+	To     N
+	Weight W
+}
+
+// WeightedGraph is a directed graph where every edge has a weight of type W.
+// Like [Graph], methods that return nodes do so in the order that the nodes were added.
+// The zero value is not usable, create a new graph using [NewWeightedGraph].
+type WeightedGraph[N comparable, W Number] struct {
+	g       *Graph[N]
+	weights map[Pair[N, N]]W
+}
+
+// NewWeightedGraph creates a new empty weighted directed graph.
+func NewWeightedGraph[N comparable, W Number]() *WeightedGraph[N, W] {
+	return &WeightedGraph[N, W]{
+		g:       NewGraph[N](),
+		weights: make(map[Pair[N, N]]W),
+	}
+}
+
+// Len returns the number of nodes.
+func (g *WeightedGraph[N, W]) Len() int {
+	return g.g.Len()
+}
+
+// EdgeCount returns the number of edges.
+func (g *WeightedGraph[N, W]) EdgeCount() int {
+	return g.g.EdgeCount()
+}
+
+// AddNode adds the node to the graph.
+// Returns true if the node was added and false if it already exists.
+func (g *WeightedGraph[N, W]) AddNode(node N) bool {
+	return g.g.AddNode(node)
+}
+
+// HasNode returns true if the node is in the graph.
+func (g *WeightedGraph[N, W]) HasNode(node N) bool {
+	return g.g.HasNode(node)
+}
+
+// Contains returns true if the node is in the graph.
+// Same as [WeightedGraph.HasNode] and implements the [Collection] interface.
+func (g *WeightedGraph[N, W]) Contains(node N) bool {
+	return g.g.HasNode(node)
+}
+
+// All returns an iterator over all the nodes in the order they were added.
+func (g *WeightedGraph[N, W]) All() iter.Seq[N] {
+	return g.g.All()
+}
+
+// RemoveNode removes the node and all the edges to and from it.
+// Returns true if the node was in the graph before removing.
+func (g *WeightedGraph[N, W]) RemoveNode(node N) bool {
+	for _, to := range g.g.Successors(node) {
+		delete(g.weights, Pair[N, N]{First: node, Second: to})
+	}
+	for _, from := range g.g.Predecessors(node) {
+		delete(g.weights, Pair[N, N]{First: from, Second: node})
+	}
+	return g.g.RemoveNode(node)
+}
+
+// AddEdge adds a directed edge from -> to with the weight. The nodes are added if they are not
+// already in the graph.
+// Returns true if the edge was added and false if the weight of an existing edge was replaced.
+func (g *WeightedGraph[N, W]) AddEdge(from N, to N, weight W) bool {
+	g.weights[Pair[N, N]{First: from, Second: to}] = weight
+	return g.g.AddEdge(from, to)
+}
+
+// AddUndirectedEdge adds the edges a -> b and b -> a with the same weight.
+func (g *WeightedGraph[N, W]) AddUndirectedEdge(a N, b N, weight W) {
+	g.AddEdge(a, b, weight)
+	g.AddEdge(b, a, weight)
+}
+
+// HasEdge returns true if the directed edge from -> to is in the graph.
+func (g *WeightedGraph[N, W]) HasEdge(from N, to N) bool {
+	return g.g.HasEdge(from, to)
+}
+
+// Weight returns the weight of the edge from -> to and true if the edge exists.
+func (g *WeightedGraph[N, W]) Weight(from N, to N) (W, bool) {
+	w, exists := g.weights[Pair[N, N]{First: from, Second: to}]
+	return w, exists
+}
+
+// RemoveEdge removes the directed edge from -> to.
+// Returns true if the edge was in the graph before removing.
+func (g *WeightedGraph[N, W]) RemoveEdge(from N, to N) bool {
+	delete(g.weights, Pair[N, N]{First: from, Second: to})
+	return g.g.RemoveEdge(from, to)
+}
+
+// Nodes returns all the nodes in the order they were added.
+func (g *WeightedGraph[N, W]) Nodes() []N {
+	return g.g.Nodes()
+}
+
+// Successors returns the nodes that have an edge from the node.
+func (g *WeightedGraph[N, W]) Successors(node N) []N {
+	return g.g.Successors(node)
+}
+
+// Predecessors returns the nodes that have an edge to the node.
+func (g *WeightedGraph[N, W]) Predecessors(node N) []N {
+	return g.g.Predecessors(node)
+}
+
+// Edges returns all the edges.
+func (g *WeightedGraph[N, W]) Edges() []WeightedEdge[N, W] {
+	result := make([]WeightedEdge[N, W], 0, g.EdgeCount())
+	for _, e := range g.g.Edges() {
+		result = append(result, WeightedEdge[N, W]{From: e.First, To: e.Second, Weight: g.weights[e]})
+	}
+	return result
+}
+
+// Unweighted returns a copy of the graph without the weights which can be used for algorithms
+// like [Graph.TopologicalSort].
+func (g *WeightedGraph[N, W]) Unweighted() *Graph[N] {
+	return g.g.Clone()
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedGraph(t *testing.T) {
+	g := collection.NewWeightedGraph[string, float64]()
+	assert.True(t, g.AddEdge("a", "b", 1.5))
+	assert.False(t, g.AddEdge("a", "b", 2.5))
+	g.AddUndirectedEdge("b", "c", 3)
+	g.AddNode("d")
+
+	assert.Equal(t, 4, g.Len())
+	assert.Equal(t, 3, g.EdgeCount())
+	assert.True(t, g.HasNode("d"))
+	assert.True(t, g.HasEdge("c", "b"))
+
+	w, ok := g.Weight("a", "b")
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, w)
+	_, ok = g.Weight("b", "a")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, g.Nodes())
+	assert.Equal(t, []string{"c"}, g.Successors("b"))
+	assert.Equal(t, []string{"a", "c"}, g.Predecessors("b"))
+	assert.Equal(t, []collection.WeightedEdge[string, float64]{
+		{From: "a", To: "b", Weight: 2.5},
+		{From: "b", To: "c", Weight: 3},
+		{From: "c", To: "b", Weight: 3},
+	}, g.Edges())
+
+	order, err := g.Unweighted().TopologicalSort()
+	assert.Error(t, err)
+	assert.Nil(t, order)
+
+	assert.True(t, g.RemoveEdge("c", "b"))
+	_, ok = g.Weight("c", "b")
+	assert.False(t, ok)
+
+	assert.True(t, g.RemoveNode("b"))
+	assert.Equal(t, 0, g.EdgeCount())
+	_, ok = g.Weight("a", "b")
+	assert.False(t, ok)
+
+	order, err = g.Unweighted().TopologicalSort()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c", "d"}, order)
+}
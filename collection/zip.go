@@ -0,0 +1,145 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrLengthMismatch is returned by [ZipStrict] when the slices have different lengths.
+var ErrLengthMismatch = errors.New("slices have different lengths")
+
+// Zip returns a new slice of pairs where the i-th pair contains the i-th element of as and bs.
+// The result has the length of the shortest slice, the remaining elements of the longer slice are ignored.
+func Zip[A any, B any](as []A, bs []B) []Pair[A, B] {
+	n := min(len(as), len(bs))
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: as[i], Second: bs[i]}
+	}
+	return result
+}
+
+// ZipLongest returns a new slice of pairs where the i-th pair contains the i-th element of as and bs.
+// The result has the length of the longest slice and the zero value is used for the missing
+// elements of the shorter slice.
+func ZipLongest[A any, B any](as []A, bs []B) []Pair[A, B] {
+	result := make([]Pair[A, B], max(len(as), len(bs)))
+	for i, a := range as {
+		result[i].First = a
+	}
+	for i, b := range bs {
+		result[i].Second = b
+	}
+	return result
+}
+
+// ZipStrict returns a new slice of pairs where the i-th pair contains the i-th element of as and bs.
+// An error wrapping [ErrLengthMismatch] is returned if the slices have different lengths.
+func ZipStrict[A any, B any](as []A, bs []B) ([]Pair[A, B], error) {
+	if len(as) != len(bs) {
+		return nil, fmt.Errorf("%w: %d and %d", ErrLengthMismatch, len(as), len(bs))
+	}
+	return Zip(as, bs), nil
+}
+
+// ZipWith returns a new slice where the i-th element is the result of calling fn with the i-th
+// element of as and bs. The result has the length of the shortest slice.
+func ZipWith[A any, B any, C any](as []A, bs []B, fn func(a A, b B) C) []C {
+	n := min(len(as), len(bs))
+	result := make([]C, n)
+	for i := 0; i < n; i++ {
+		result[i] = fn(as[i], bs[i])
+	}
+	return result
+}
+
+// Unzip splits the pairs into two new slices containing the first and second values.
+func Unzip[A any, B any](pairs []Pair[A, B]) ([]A, []B) {
+	as := make([]A, len(pairs))
+	bs := make([]B, len(pairs))
+	for i, p := range pairs {
+		as[i] = p.First
+		bs[i] = p.Second
+	}
+	return as, bs
+}
+
+// Enumerate returns a new slice of pairs where each element is paired with its index.
+func Enumerate[T any](s []T) []Pair[int, T] {
+	result := make([]Pair[int, T], len(s))
+	for i, item := range s {
+		result[i] = Pair[int, T]{First: i, Second: item}
+	}
+	return result
+}
+
+//-----------------------------------------------------------------------------
+// Iterator adapters
+
+// ZipSeq returns an iterator over the values of both iterators in lock step.
+// The iteration stops as soon as either of the iterators is exhausted.
+func ZipSeq[A any, B any](as iter.Seq[A], bs iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		nextB, stop := iter.Pull(bs)
+		defer stop()
+		for a := range as {
+			b, ok := nextB()
+			if !ok || !yield(a, b) {
+				return
+			}
+		}
+	}
+}
+
+// EnumerateSeq returns an iterator over the values of seq paired with their index.
+func EnumerateSeq[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for item := range seq {
+			if !yield(i, item) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// PairsSeq returns an iterator over the first and second values of the pairs.
+func PairsSeq[A any, B any](pairs []Pair[A, B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		for _, p := range pairs {
+			if !yield(p.First, p.Second) {
+				return
+			}
+		}
+	}
+}
+
+// CollectPairs returns a new slice with a pair for every key-value pair produced by the iterator.
+func CollectPairs[A any, B any](seq iter.Seq2[A, B]) []Pair[A, B] {
+	result := make([]Pair[A, B], 0)
+	for a, b := range seq {
+		result = append(result, Pair[A, B]{First: a, Second: b})
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Andre Jacobs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package collection_test
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/andrejacobs/go-collection/collection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type intString = collection.Pair[int, string]
+
+func TestZip(t *testing.T) {
+	as := []int{1, 2, 3}
+	bs := []string{"a", "b"}
+
+	assert.Equal(t, []intString{{1, "a"}, {2, "b"}}, collection.Zip(as, bs))
+	assert.Equal(t, []intString{{1, "a"}, {2, "b"}, {3, ""}}, collection.ZipLongest(as, bs))
+	assert.Equal(t, []collection.Pair[string, int]{{"a", 1}, {"b", 2}, {"", 3}}, collection.ZipLongest(bs, as))
+	assert.Equal(t, []intString{}, collection.Zip([]int{}, bs))
+
+	_, err := collection.ZipStrict(as, bs)
+	require.ErrorIs(t, err, collection.ErrLengthMismatch)
+	assert.Contains(t, err.Error(), "3 and 2")
+
+	pairs, err := collection.ZipStrict(as[:2], bs)
+	require.NoError(t, err)
+	assert.Equal(t, []intString{{1, "a"}, {2, "b"}}, pairs)
+}
+
+func TestZipWithAndUnzip(t *testing.T) {
+	as := []int{1, 2, 3}
+	bs := []string{"a", "b", "c", "d"}
+
+	joined := collection.ZipWith(as, bs, func(a int, b string) string { return b + strconv.Itoa(a) })
+	assert.Equal(t, []string{"a1", "b2", "c3"}, joined)
+
+	gotA, gotB := collection.Unzip(collection.Zip(as, bs))
+	assert.Equal(t, as, gotA)
+	assert.Equal(t, bs[:3], gotB)
+
+	gotA, gotB = collection.Unzip([]intString{})
+	assert.Empty(t, gotA)
+	assert.Empty(t, gotB)
+}
+
+func TestEnumerate(t *testing.T) {
+	assert.Equal(t, []collection.Pair[int, string]{{0, "a"}, {1, "b"}}, collection.Enumerate([]string{"a", "b"}))
+	assert.Empty(t, collection.Enumerate([]string{}))
+
+	var indexes []int
+	for i, s := range collection.EnumerateSeq(slices.Values([]string{"a", "b", "c"})) {
+		indexes = append(indexes, i)
+		if s == "b" {
+			break
+		}
+	}
+	assert.Equal(t, []int{0, 1}, indexes)
+}
+
+func TestZipSeq(t *testing.T) {
+	as := slices.Values([]int{1, 2, 3})
+	bs := slices.Values([]string{"a", "b"})
+	assert.Equal(t, []intString{{1, "a"}, {2, "b"}}, collection.CollectPairs(collection.ZipSeq(as, bs)))
+	longer := slices.Values([]string{"a", "b", "c", "d"})
+	assert.Equal(t, []intString{{1, "a"}, {2, "b"}, {3, "c"}}, collection.CollectPairs(collection.ZipSeq(as, longer)))
+
+	count := 0
+	for range collection.ZipSeq(as, bs) {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestPairsSeq(t *testing.T) {
+	pairs := []intString{{1, "a"}, {2, "b"}}
+	assert.Equal(t, map[int]string{1: "a", 2: "b"}, maps.Collect(collection.PairsSeq(pairs)))
+	assert.Equal(t, pairs, collection.CollectPairs(collection.PairsSeq(pairs)))
+	assert.Equal(t, []intString{}, collection.CollectPairs(collection.PairsSeq([]intString{})))
+}